@@ -0,0 +1,132 @@
+package Maze
+
+import (
+	"math"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// ---------------------------- Camera ---------------------------- //
+
+const (
+	camera_max_zoom  = 16
+	camera_min_tile  = 24 // Smallest tile (pixels) of the initial zoom, so big maps are readable
+	camera_pan_speed = 10 // Pixels of the window moved on each frame by the pan keys
+	camera_zoom_step = 1.25
+)
+
+// View of the board. The board is drawn with the whole grid stretched over
+// its area of the window (zoom 1), and the camera zooms and moves over it, so
+// the HUD outside the board isn't changed
+type camera struct {
+	board        pixel.Rect // Area of the window used by the board
+	center       pixel.Vec  // Point of the board (zoom 1) shown on the center of the area
+	zoom         float64
+	initial_zoom float64
+	follow       bool // Follow the player (human) or the best individual (automation)
+}
+
+// Camera over the board with the height of the window, zoomed in just when
+// the tiles would be too small
+func new_camera(grid *Grid, height float64) *camera {
+	board := pixel.R(0, 0, screen_width, height)
+
+	zoom := 1.0
+	if tile := math.Min(board.W()/float64(grid.Width()), board.H()/float64(grid.Height())); tile < camera_min_tile {
+		zoom = math.Min(camera_min_tile/tile, camera_max_zoom)
+	}
+
+	return &camera{board: board, center: board.Center(), zoom: zoom, initial_zoom: zoom, follow: true}
+}
+
+// Matrix of the board drawing (set on the window before drawing the board)
+func (cam *camera) matrix() pixel.Matrix {
+	return pixel.IM.Moved(cam.center.Scaled(-1)).Scaled(pixel.ZV, cam.zoom).Moved(cam.board.Center())
+}
+
+// Keep the view inside the board
+func (cam *camera) clamp() {
+	half := cam.board.Size().Scaled(0.5 / cam.zoom)
+	cam.center.X = math.Max(cam.board.Min.X+half.X, math.Min(cam.center.X, cam.board.Max.X-half.X))
+	cam.center.Y = math.Max(cam.board.Min.Y+half.Y, math.Min(cam.center.Y, cam.board.Max.Y-half.Y))
+}
+
+// Change the zoom keeping the point of the window on the same place
+func (cam *camera) zoom_by(factor float64, around pixel.Vec) {
+	point := cam.matrix().Unproject(around)
+
+	cam.zoom = math.Max(1, math.Min(cam.zoom*factor, camera_max_zoom))
+	cam.center = point.Sub(around.Sub(cam.board.Center()).Scaled(1 / cam.zoom))
+	cam.clamp()
+}
+
+// Center the view on the cell of the grid
+func (cam *camera) look_at(grid *Grid, pos Position) {
+	cam.center = getObjectGridPosition(cam.board.W(), cam.board.H(), grid.Width(), grid.Height(), pos.X, pos.Y).Center()
+	cam.clamp()
+}
+
+// Zoom with the mouse wheel, + and - (0 goes back to the initial zoom), move
+// with the pan keys (up, down, left, right) or dragging with the middle
+// button, and F turns the follow mode on and off. Moving the view stops
+// following
+func (cam *camera) control(win *pixelgl.Window, pan [4]pixelgl.Button) {
+
+	// Zoom
+	if scroll := win.MouseScroll().Y; scroll != 0 && cam.board.Contains(win.MousePosition()) {
+		cam.zoom_by(math.Pow(camera_zoom_step, scroll), win.MousePosition())
+	}
+	if win.JustPressed(pixelgl.KeyEqual) || win.JustPressed(pixelgl.KeyKPAdd) {
+		cam.zoom_by(camera_zoom_step, cam.board.Center())
+	}
+	if win.JustPressed(pixelgl.KeyMinus) || win.JustPressed(pixelgl.KeyKPSubtract) {
+		cam.zoom_by(1/camera_zoom_step, cam.board.Center())
+	}
+	if win.JustPressed(pixelgl.Key0) || win.JustPressed(pixelgl.KeyKP0) {
+		cam.zoom_by(cam.initial_zoom/cam.zoom, cam.board.Center())
+	}
+
+	// Pan
+	var moved pixel.Vec
+	if win.Pressed(pan[up]) {
+		moved.Y += camera_pan_speed
+	}
+	if win.Pressed(pan[down]) {
+		moved.Y -= camera_pan_speed
+	}
+	if win.Pressed(pan[left]) {
+		moved.X -= camera_pan_speed
+	}
+	if win.Pressed(pan[right]) {
+		moved.X += camera_pan_speed
+	}
+	if win.Pressed(pixelgl.MouseButtonMiddle) {
+		moved = moved.Sub(win.MousePosition().Sub(win.MousePreviousPosition()))
+	}
+	if moved != pixel.ZV {
+		cam.center = cam.center.Add(moved.Scaled(1 / cam.zoom))
+		cam.clamp()
+		cam.follow = false
+	}
+
+	if win.JustPressed(pixelgl.KeyF) {
+		cam.follow = !cam.follow
+	}
+}
+
+// Position followed by the camera: the player (human) or the individual
+// closest to an exit (automation)
+func (sim *Simulation) leading_position() Position {
+	leader := sim.players[0]
+	distance := sim.Grid.Distance(leader.grid_pos_X, leader.grid_pos_Y, leader.keys)
+
+	if sim.Config.Automation {
+		for _, object := range sim.players[1:] {
+			if d := sim.Grid.Distance(object.grid_pos_X, object.grid_pos_Y, object.keys); d >= 0 && (distance < 0 || d < distance) {
+				leader, distance = object, d
+			}
+		}
+	}
+	return Position{leader.grid_pos_X, leader.grid_pos_Y}
+}
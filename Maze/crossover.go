@@ -0,0 +1,272 @@
+package Maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// ----------------------- Crossover Operators -------------------- //
+
+// Combine two parents into two children. The children are buffers with the
+// same size of the parents, filled by the operator. The traces are the runs
+// of the parents through the maze (used by the operators that look at the grid)
+type Crossover interface {
+	Cross(rng *rand.Rand, father1 Genome, father2 Genome, child1 Genome, child2 Genome, trace1 *Trace, trace2 *Trace)
+}
+
+// Operators with parameters check them when created by NewCrossover
+type crossover_validator interface {
+	validate() error
+}
+
+// Parameters of the built-in crossover operators
+type CrossoverParams struct {
+	Points       int     // K-point: number of cut points
+	Uniform_rate float64 // Uniform: chance of swapping each gene
+	Aligned      bool    // Cut just between commands (pairs of genes), so a command is never split
+}
+
+// Default parameters of the INI file
+var Default_crossover_params = CrossoverParams{
+	Points:       3,
+	Uniform_rate: 0.5,
+	Aligned:      false,
+}
+
+// Built-in crossover operators, selected by the INI file ([Settings] Crossover)
+var crossover_operators = map[string]func(params CrossoverParams) Crossover{
+	"single":        func(p CrossoverParams) Crossover { return PointCrossover{Points: 1, Aligned: p.Aligned} },
+	"two-point":     func(p CrossoverParams) Crossover { return PointCrossover{Points: 2, Aligned: p.Aligned} },
+	"k-point":       func(p CrossoverParams) Crossover { return PointCrossover{Points: p.Points, Aligned: p.Aligned} },
+	"uniform":       func(p CrossoverParams) Crossover { return UniformCrossover{Rate: p.Uniform_rate, Aligned: p.Aligned} },
+	"command":       func(p CrossoverParams) Crossover { return PointCrossover{Points: 1, Aligned: true} },
+	"same-position": func(p CrossoverParams) Crossover { return SamePositionCrossover{} },
+}
+
+// Create a built-in crossover operator by its name
+func NewCrossover(name string, params CrossoverParams) (Crossover, error) {
+	operator, ok := crossover_operators[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("crossover operator '%s' not found (options: %s)", name, strings.Join(CrossoverNames(), ", "))
+	}
+
+	// Just the parameters used by the operator are checked
+	crossover := operator(params)
+	if validator, ok := crossover.(crossover_validator); ok {
+		if err := validator.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return crossover, nil
+}
+
+// Names of the built-in crossover operators
+func CrossoverNames() []string {
+	var names []string
+	for name := range crossover_operators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Random cut points in [1, genes), or just on even positions (between
+// commands) when aligned. The cuts are chosen while the genes are visited, so
+// there is no slice of cut points (selection sampling)
+type cut_sampler struct {
+	rng        *rand.Rand
+	needed     int // Cuts still to be chosen
+	candidates int // Positions still to be visited
+	single     int // Position of the cut when there is just one (a single random number)
+}
+
+func new_cut_sampler(rng *rand.Rand, genes int, points int, aligned bool) cut_sampler {
+	candidates := genes - 1
+	if aligned {
+		candidates = (genes - 1) / 2
+	}
+	if points > candidates {
+		points = candidates
+	}
+
+	sampler := cut_sampler{rng: rng, needed: points, candidates: candidates, single: -1}
+	if points == 1 {
+		sampler.single = rng.Intn(candidates)
+	}
+	return sampler
+}
+
+// Check if the next candidate position is a cut
+func (sampler *cut_sampler) cut() bool {
+	var selected bool
+
+	if sampler.single >= 0 {
+		selected = sampler.single == 0
+		sampler.single--
+	} else if sampler.needed > 0 {
+		selected = sampler.rng.Intn(sampler.candidates) < sampler.needed
+	}
+
+	sampler.candidates--
+	if selected {
+		sampler.needed--
+	}
+	return selected
+}
+
+// -------------------------- K-point ----------------------------- //
+
+// The parents are cut on Points random positions and the children receive
+// the parts alternately (Points = 1 is the original single-point crossover)
+// A cut between the two genes of a command mixes the commands of the parents
+type PointCrossover struct {
+	Points  int
+	Aligned bool
+}
+
+func (c PointCrossover) Cross(rng *rand.Rand, father1 Genome, father2 Genome, child1 Genome, child2 Genome, trace1 *Trace, trace2 *Trace) {
+	sampler := new_cut_sampler(rng, father1.genes(), c.Points, c.Aligned)
+	swapped := false
+
+	for command := range father1 {
+		// Cut before the first gene of the command
+		if command > 0 && sampler.cut() {
+			swapped = !swapped
+		}
+		first_swapped := swapped
+
+		// Cut between the genes of the command
+		if !c.Aligned && sampler.cut() {
+			swapped = !swapped
+		}
+
+		gene1_from1, gene1_from2 := father1[command]&2, father2[command]&2
+		if first_swapped {
+			gene1_from1, gene1_from2 = gene1_from2, gene1_from1
+		}
+		gene2_from1, gene2_from2 := father1[command]&1, father2[command]&1
+		if swapped {
+			gene2_from1, gene2_from2 = gene2_from2, gene2_from1
+		}
+
+		child1[command] = gene1_from1 | gene2_from1
+		child2[command] = gene1_from2 | gene2_from2
+	}
+}
+
+func (c PointCrossover) validate() error {
+	if c.Points < 1 {
+		return fmt.Errorf("number of crossover points must be at least 1")
+	}
+	return nil
+}
+
+// -------------------------- Uniform ----------------------------- //
+
+// Each gene (or command, when aligned) is swapped between the children with
+// the chance Rate
+type UniformCrossover struct {
+	Rate    float64
+	Aligned bool
+}
+
+func (c UniformCrossover) Cross(rng *rand.Rand, father1 Genome, father2 Genome, child1 Genome, child2 Genome, trace1 *Trace, trace2 *Trace) {
+	for command := range father1 {
+		var mask Direction
+
+		if c.Aligned {
+			if rng.Float64() < c.Rate {
+				mask = 3
+			}
+		} else {
+			if rng.Float64() < c.Rate {
+				mask |= 2
+			}
+			if rng.Float64() < c.Rate {
+				mask |= 1
+			}
+		}
+
+		// Genes on the mask come from the other parent
+		child1[command] = father1[command]&^mask | father2[command]&mask
+		child2[command] = father2[command]&^mask | father1[command]&mask
+	}
+}
+
+func (c UniformCrossover) validate() error {
+	if c.Rate < 0 || c.Rate > 1 {
+		return fmt.Errorf("uniform rate should be between 0 and 1")
+	}
+	return nil
+}
+
+// ------------------------ Same Position ------------------------- //
+
+// Cut the parents where both were on the same cell of the grid (not
+// necessarily on the same step), so each child follows the route of one
+// parent until the meeting cell and then the route of the other one.
+// When the cut steps are different, the child is truncated or completed with
+// the commands of its first parent to keep the genome size.
+// Without a common cell (besides the start), a command aligned cut is used
+type SamePositionCrossover struct{}
+
+func (SamePositionCrossover) Cross(rng *rand.Rand, father1 Genome, father2 Genome, child1 Genome, child2 Genome, trace1 *Trace, trace2 *Trace) {
+	var (
+		cut1, cut2 int
+		meetings   int
+	)
+
+	// First time that the first parent was on each cell
+	first_step := make(map[Position]int, len(trace1.Path))
+	for step, pos := range trace1.Path {
+		if _, ok := first_step[pos]; !ok {
+			first_step[pos] = step
+		}
+	}
+
+	// Cells visited by both parents (each cell just once), one of them is
+	// chosen with the same chance (reservoir sampling)
+	for step2, pos := range trace2.Path {
+		step1, ok := first_step[pos]
+		if !ok || step1 < 0 || (step1 == 0 && step2 == 0) {
+			continue
+		}
+		first_step[pos] = -1 // Visited
+
+		meetings++
+		if rng.Intn(meetings) == 0 {
+			cut1, cut2 = step1, step2
+		}
+	}
+
+	if meetings == 0 {
+		PointCrossover{Points: 1, Aligned: true}.Cross(rng, father1, father2, child1, child2, trace1, trace2)
+		return
+	}
+
+	// Each step is one command
+	if cut1 > len(father1) {
+		cut1 = len(father1)
+	}
+	if cut2 > len(father2) {
+		cut2 = len(father2)
+	}
+
+	if debug {
+		fmt.Printf("\t\tSame position: commands %d and %d\n", cut1, cut2)
+	}
+
+	join_at_cut(child1, father1, father2, cut1, cut2)
+	join_at_cut(child2, father2, father1, cut2, cut1)
+}
+
+// First part of father1 (until cut1) with the rest of father2 (from cut2),
+// completed with father1 to keep the size
+func join_at_cut(child Genome, father1 Genome, father2 Genome, cut1 int, cut2 int) {
+	copy(child, father1[:cut1])
+	size := cut1 + copy(child[cut1:], father2[cut2:])
+	copy(child[size:], father1[size:])
+}
@@ -0,0 +1,32 @@
+package Maze
+
+import "testing"
+
+// Each operator checks just its own parameters
+
+func TestNewCrossover(t *testing.T) {
+	invalid := CrossoverParams{Points: 0, Uniform_rate: 2}
+
+	for _, name := range []string{"single", "two-point", "command", "same-position"} {
+		if _, err := NewCrossover(name, invalid); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		valid CrossoverParams // Invalid parameters, but the ones of the operator
+	}{
+		{"k-point", CrossoverParams{Points: 3, Uniform_rate: 2}},
+		{"uniform", CrossoverParams{Points: 0, Uniform_rate: 0.5}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewCrossover(test.name, invalid); err == nil {
+			t.Errorf("%s: the invalid parameters were accepted", test.name)
+		}
+		if _, err := NewCrossover(test.name, test.valid); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package Maze
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/pixel"
+	"github.com/faiface/pixel/imdraw"
+	"github.com/faiface/pixel/pixelgl"
+	"github.com/faiface/pixel/text"
+	"golang.org/x/image/colornames"
+)
+
+// -------------------------- Map Editor -------------------------- //
+
+// Map being edited on the window. The grid isn't validated while it's
+// edited, just when it's saved or tested
+type editor struct {
+	config  Config
+	grid    *Grid
+	path    string
+	message string // Result of the last action, shown on the top of the window
+}
+
+// Edit a map file on the window (a new map of the size, like 20x10, when the
+// file doesn't exist yet)
+//
+//	Click          cycle the tile (path, trees 1 - 4, keys, doors, hazards, teleporters and one-way tiles), right click goes back
+//	Shift + click  place the start
+//	Ctrl + click   add or remove an exit
+//	S              save the map file
+//	T / G          test the map playing with the keyboard / with the genetic algorithm
+//	Wheel, + / -   zoom, the arrows or the middle button move the view
+//	Esc            quit (or go back to the editor from a test)
+func Edit(config Config, path string, size string) {
+	e := &editor{config: config, path: path}
+
+	if _, err := os.Stat(path); err == nil {
+		e.grid, err = load_editor_map(path)
+		if err != nil {
+			fmt.Printf("\n%s. Exiting\n", err)
+			os.Exit(2)
+		}
+		e.message = fmt.Sprintf("Editing %s", path)
+		if _, err := e.check(); err != nil {
+			e.message = fmt.Sprintf("Editing %s, it can't be played yet:\n%s", path, err)
+		}
+	} else {
+		width, height, err := parse_size(size)
+		if err != nil {
+			fmt.Printf("\n%s. Exiting\n", err)
+			os.Exit(2)
+		}
+		e.grid = blank_grid(width, height)
+		e.message = fmt.Sprintf("New map %s (%d x %d)", path, width, height)
+	}
+
+	win := new_window()
+
+	// The mouse is used to edit
+	win.SetCursorVisible(true)
+
+	// Same sprites of the game
+	spriteMap, err := loadPicture("Images/spritemap-rpg.png")
+	if err != nil {
+		panic(err)
+	}
+	bgd := &background{}
+	bgd.setPlayerSprites()
+
+	// Camera over the board, moved with the arrows
+	cam := new_camera(e.grid, debug_screen_bottom)
+	cam.follow = false
+	pan_keys := [4]pixelgl.Button{up: pixelgl.KeyUp, down: pixelgl.KeyDown, left: pixelgl.KeyLeft, right: pixelgl.KeyRight}
+
+	for !win.Closed() {
+
+		// Esc to quit the editor
+		if win.JustPressed(pixelgl.KeyEscape) {
+			break
+		}
+
+		cam.control(win, pan_keys)
+
+		// ----------------------- Mouse ------------------------ //
+
+		pos, inside := cell_at(cam.matrix().Unproject(win.MousePosition()), e.grid, debug_screen_bottom)
+		inside = inside && cam.board.Contains(win.MousePosition())
+		shift := win.Pressed(pixelgl.KeyLeftShift) || win.Pressed(pixelgl.KeyRightShift)
+		ctrl := win.Pressed(pixelgl.KeyLeftControl) || win.Pressed(pixelgl.KeyRightControl)
+
+		if inside && win.JustPressed(pixelgl.MouseButtonLeft) {
+			if shift {
+				e.set_start(pos)
+			} else if ctrl {
+				e.toggle_exit(pos)
+			} else {
+				e.cycle_tile(pos, 1)
+			}
+		}
+		if inside && win.JustPressed(pixelgl.MouseButtonRight) {
+			e.cycle_tile(pos, -1)
+		}
+
+		// ---------------------- Keyboard ---------------------- //
+
+		if win.JustPressed(pixelgl.KeyS) {
+			e.save()
+		}
+		if win.JustPressed(pixelgl.KeyT) {
+			e.test(win, false)
+		}
+		if win.JustPressed(pixelgl.KeyG) {
+			e.test(win, true)
+		}
+
+		// ------------------------ Draw ------------------------ //
+
+		imd := imdraw.New(spriteMap)
+		hud := imdraw.New(nil)
+		win.Clear(colornames.Lightgreen)
+
+		// Help and messages on the top, like the debug screen
+		hud.Color = colornames.Gray
+		hud.Push(pixel.V(0, debug_screen_bottom))
+		hud.Push(pixel.V(800, 800))
+		hud.Rectangle(0)
+
+		hud.Color = colornames.Whitesmoke
+		hud.Push(pixel.V(5, debug_screen_bottom+5))
+		hud.Push(pixel.V(795, 795))
+		hud.Rectangle(0)
+
+		// Same drawing of the game, with the camera
+		bgd.draw(imd, e.grid, debug_screen_bottom)
+		draw_keys_doors(imd, e.grid, 0, debug_screen_bottom)
+		draw_hazards(imd, e.grid, debug_screen_bottom)
+		draw_moves(imd, e.grid, debug_screen_bottom)
+		draw_enemies(imd, spriteMap, e.grid, 0, debug_screen_bottom)
+		e.draw_markers(imd)
+		win.SetMatrix(cam.matrix())
+		imd.Draw(win)
+		win.SetMatrix(pixel.IM)
+		hud.Draw(win)
+
+		textMessage = text.New(pixel.V(20, 770), atlas)
+		textMessage.Color = colornames.Black
+		fmt.Fprintf(textMessage, "Map Editor: %s (%d x %d)\n\n", e.path, e.grid.Width(), e.grid.Height())
+		fmt.Fprintf(textMessage, "Click: change tile (right click: back)    Shift + click: start    Ctrl + click: exit\n")
+		fmt.Fprintf(textMessage, "S: save    T: test playing    G: test with the genetic algorithm    Esc: quit\n")
+		fmt.Fprintf(textMessage, "Mouse wheel, + and -: zoom (%.1fx)    Arrows or middle button: move\n\n", cam.zoom)
+		fmt.Fprintf(textMessage, "%s", e.message)
+		textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+		win.Update()
+	}
+}
+
+// Map with trees on the border, the start on the left and the exit on the right
+func blank_grid(width int, height int) *Grid {
+	grid := &Grid{Cells: make([][]uint8, height)}
+
+	for line := range grid.Cells {
+		grid.Cells[line] = make([]uint8, width)
+		for column := range grid.Cells[line] {
+			if line == 0 || line == height-1 || column == 0 || column == width-1 {
+				grid.Cells[line][column] = 1
+			}
+		}
+	}
+
+	grid.Start = Position{0, height / 2}
+	grid.Exits = []Position{{width - 1, height / 2}}
+	grid.Cells[height-1-height/2][0] = 0
+	grid.Cells[height-1-height/2][width-1] = 0
+
+	return grid
+}
+
+// Map file to be edited. The text maps are loaded even with problems on the
+// layout (unreachable exits, border, teleporters, enemies), to be fixed here
+func load_editor_map(path string) (*Grid, error) {
+	if tiled_map_file(path) {
+		return LoadMap(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("map '%s' not found: %w", path, err)
+	}
+	defer file.Close()
+
+	m, err := read_map(file)
+	if err != nil {
+		return nil, fmt.Errorf("map '%s': %w", path, err)
+	}
+	return m.unchecked_grid(), nil
+}
+
+// Cell of the grid on the point of the board (the inverse of getObjectGridPosition)
+func cell_at(point pixel.Vec, grid *Grid, height float64) (Position, bool) {
+	if point.X < 0 || point.X >= screen_width || point.Y < 0 || point.Y >= height {
+		return Position{}, false
+	}
+
+	x := int(point.X / (screen_width / float64(grid.Width())))
+	y := int(point.Y / (height / float64(grid.Height())))
+	return Position{x, y}, true
+}
+
+// Set the tile on the player coordinates
+func (e *editor) set_tile(pos Position, tile uint8) {
+	e.grid.Cells[e.grid.Height()-1-pos.Y][pos.X] = tile
+}
+
+// Tiles of the clicks, in order: path, trees, keys, doors, hazards,
+// teleporters and one-way tiles
+var editor_tiles = []uint8{
+	0, 1, 2, 3, 4,
+	key_tile, key_tile + 1, key_tile + 2, key_tile + 3,
+	door_tile, door_tile + 1, door_tile + 2, door_tile + 3,
+	hazard_tile, hazard_tile + 1, hazard_tile + 2,
+	teleporter_tile, teleporter_tile + 1, teleporter_tile + 2, teleporter_tile + 3,
+	one_way_tile + uint8(up), one_way_tile + uint8(down), one_way_tile + uint8(left), one_way_tile + uint8(right),
+}
+
+// Next tile of the cell (step 1 goes forward, -1 goes back), the start and the
+// exits of the cell are removed
+func (e *editor) cycle_tile(pos Position, step int) {
+	index := 0
+	for i, tile := range editor_tiles {
+		if tile == e.grid.Tile(pos.X, pos.Y) {
+			index = i
+		}
+	}
+	e.set_tile(pos, editor_tiles[(index+step+len(editor_tiles))%len(editor_tiles)])
+	e.remove_exit(pos)
+	if e.grid.Start == pos {
+		e.grid.Start = Position{-1, -1}
+	}
+}
+
+// Move the start to the cell
+func (e *editor) set_start(pos Position) {
+	if e.grid.Start.X >= 0 {
+		e.close_border(e.grid.Start)
+	}
+	e.remove_exit(pos)
+	e.set_tile(pos, 0)
+	e.grid.Start = pos
+}
+
+// Add an exit on the cell, or remove the exit that is there
+func (e *editor) toggle_exit(pos Position) {
+	if e.grid.is_exit(pos) {
+		e.remove_exit(pos)
+		e.close_border(pos)
+		return
+	}
+
+	if e.grid.Start == pos {
+		e.grid.Start = Position{-1, -1}
+	}
+	e.set_tile(pos, 0)
+	e.grid.Exits = append(e.grid.Exits, pos)
+}
+
+func (e *editor) remove_exit(pos Position) {
+	for i, exit := range e.grid.Exits {
+		if exit == pos {
+			e.grid.Exits = append(e.grid.Exits[:i], e.grid.Exits[i+1:]...)
+			return
+		}
+	}
+}
+
+// Put a tree back on the opening of the border that isn't used anymore
+func (e *editor) close_border(pos Position) {
+	if pos.X == 0 || pos.X == e.grid.Width()-1 || pos.Y == 0 || pos.Y == e.grid.Height()-1 {
+		e.set_tile(pos, 1)
+	}
+}
+
+// Validated copy of the map being edited, like it's loaded from the file
+func (e *editor) check() (*Grid, error) {
+	if e.grid.Start.X < 0 {
+		return nil, fmt.Errorf("the map needs one start (S)")
+	}
+	if len(e.grid.Exits) == 0 {
+		return nil, fmt.Errorf("the map needs at least one exit (E)")
+	}
+
+	// The validation counts the lines from the top
+	height := e.grid.Height()
+	cells := make([][]uint8, height)
+	for line := range cells {
+		cells[line] = append([]uint8(nil), e.grid.Cells[line]...)
+	}
+	start := Position{e.grid.Start.X, height - 1 - e.grid.Start.Y}
+	var exits []Position
+	for _, exit := range e.grid.Exits {
+		exits = append(exits, Position{exit.X, height - 1 - exit.Y})
+	}
+
+	grid, err := new_grid(cells, start, exits)
+	if err != nil {
+		return nil, err
+	}
+
+	// The trees changed under the enemies are found here
+	for _, waypoints := range e.grid.enemy_waypoints() {
+		if err := grid.add_enemy(waypoints); err != nil {
+			return nil, err
+		}
+	}
+	return grid, nil
+}
+
+// Save the map file, even with problems (they are shown to be fixed later)
+func (e *editor) save() {
+	if tiled_map_file(e.path) {
+		e.message = "The maps of Tiled are saved on Tiled, edit a .map file to save it here"
+		return
+	}
+
+	file, err := os.Create(e.path)
+	if err != nil {
+		e.message = fmt.Sprintf("Error saving: %s", err)
+		return
+	}
+	defer file.Close()
+
+	title := "Map " + strings.TrimSuffix(filepath.Base(e.path), filepath.Ext(e.path))
+	if err := e.grid.WriteMap(file, title); err != nil {
+		e.message = fmt.Sprintf("Error saving: %s", err)
+		return
+	}
+
+	if _, err := e.check(); err != nil {
+		e.message = fmt.Sprintf("Saved to %s, but it can't be played yet:\n%s", e.path, err)
+		return
+	}
+	e.message = fmt.Sprintf("Saved to %s", e.path)
+}
+
+// Play or evolve the map being edited on the same window, Esc goes back to the editor
+func (e *editor) test(win *pixelgl.Window, automation bool) {
+	grid, err := e.check()
+	if err != nil {
+		e.message = fmt.Sprintf("The map can't be tested: %s", err)
+		return
+	}
+
+	cfg := e.config
+	cfg.Automation = automation
+	sim, err := new_simulation(cfg, grid)
+	if err != nil {
+		e.message = fmt.Sprintf("The map can't be tested: %s", err)
+		return
+	}
+
+	win.SetCursorVisible(false)
+	sim.show(win)
+	win.SetCursorVisible(true)
+
+	// Clear the Esc pressed to leave the test
+	win.Update()
+
+	e.message = fmt.Sprintf("Test finished (best solution: %d steps)", grid.Best_solution)
+}
+
+// Draw the start (blue) and the exits (red) over the board
+func (e *editor) draw_markers(imd *imdraw.IMDraw) {
+	marker := func(pos Position) {
+		cell := getObjectGridPosition(screen_width, debug_screen_bottom, e.grid.Width(), e.grid.Height(), pos.X, pos.Y)
+		imd.Push(cell.Min.Add(pixel.V(2, 2)), cell.Max.Sub(pixel.V(2, 2)))
+		imd.Rectangle(3)
+	}
+
+	if e.grid.Start.X >= 0 {
+		imd.Color = colornames.Blue
+		marker(e.grid.Start)
+	}
+
+	imd.Color = colornames.Red
+	for _, exit := range e.grid.Exits {
+		marker(exit)
+	}
+}
@@ -0,0 +1,165 @@
+package Maze
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// --------------------------- Enemies ---------------------------- //
+
+// What happens to the players caught by an enemy (Config.Enemy_collision)
+const (
+	Enemy_kills    = "kill"    // The run ends, like on the hazards
+	Enemy_restarts = "restart" // Back to the start, keeping the keys
+)
+
+// Enemy that patrols the map, one tile on each cycle. The Route is the same
+// on every generation, so the individuals can learn the timing
+type Patrol struct {
+	Waypoints []Position // Tiles of the map file where the route turns
+	Route     []Position // Position on each cycle, repeated after the end
+}
+
+// Position of the enemy on the cycle (step of the players)
+func (patrol Patrol) At(step int) Position {
+	return patrol.Route[step%len(patrol.Route)]
+}
+
+// Direction of the next tile of the route (down for the enemies that don't move)
+func (patrol Patrol) facing(step int) Direction {
+	current, next := patrol.At(step), patrol.At(step+1)
+	switch {
+	case next.X > current.X:
+		return right
+	case next.X < current.X:
+		return left
+	case next.Y > current.Y:
+		return up
+	}
+	return down
+}
+
+// Tiles where the enemies walk: the path and the keys (they don't pick them up)
+func enemy_walkable(tile uint8) bool {
+	_, key := key_color(tile)
+	return tile == 0 || key
+}
+
+// Add an enemy that walks straight from each waypoint to the next one and goes
+// back the same way, or starts again when the last waypoint is the first one.
+// The route can't cross the start, the players restarted there would be caught
+// again. The waypoints count the lines from the top (like new_grid)
+func (grid *Grid) add_enemy(waypoints []Position) error {
+	height := grid.Height()
+	inside := func(pos Position) bool {
+		return pos.X >= 0 && pos.X < grid.Width() && pos.Y >= 0 && pos.Y < height
+	}
+
+	for _, waypoint := range waypoints {
+		if !inside(waypoint) {
+			return fmt.Errorf("enemy: the tile %d,%d is outside the map", waypoint.X+1, waypoint.Y+1)
+		}
+	}
+	if !enemy_walkable(grid.Cells[waypoints[0].Y][waypoints[0].X]) {
+		return fmt.Errorf("enemy: the tile %d,%d isn't a path", waypoints[0].X+1, waypoints[0].Y+1)
+	}
+
+	// Tiles of the way there
+	route := []Position{waypoints[0]}
+	for i := 1; i < len(waypoints); i++ {
+		from, to := waypoints[i-1], waypoints[i]
+		if from.X != to.X && from.Y != to.Y {
+			return fmt.Errorf("enemy: the tiles %d,%d and %d,%d aren't on the same line or column", from.X+1, from.Y+1, to.X+1, to.Y+1)
+		}
+
+		for pos := from; pos != to; {
+			if pos.X < to.X {
+				pos.X++
+			} else if pos.X > to.X {
+				pos.X--
+			} else if pos.Y < to.Y {
+				pos.Y++
+			} else {
+				pos.Y--
+			}
+			if !enemy_walkable(grid.Cells[pos.Y][pos.X]) {
+				return fmt.Errorf("enemy: the tile %d,%d of the route isn't a path", pos.X+1, pos.Y+1)
+			}
+			route = append(route, pos)
+		}
+	}
+
+	start := Position{grid.Start.X, height - 1 - grid.Start.Y}
+	for _, pos := range route {
+		if pos == start {
+			return fmt.Errorf("enemy: the route crosses the start (S) on the tile %d,%d", pos.X+1, pos.Y+1)
+		}
+	}
+
+	// Loop or the way back
+	if len(route) > 1 && route[len(route)-1] == route[0] {
+		route = route[:len(route)-1]
+	} else {
+		for i := len(route) - 2; i > 0; i-- {
+			route = append(route, route[i])
+		}
+	}
+
+	// Players coordinates count the Y axis from the bottom
+	patrol := Patrol{}
+	for _, waypoint := range waypoints {
+		patrol.Waypoints = append(patrol.Waypoints, Position{waypoint.X, height - 1 - waypoint.Y})
+	}
+	for _, pos := range route {
+		patrol.Route = append(patrol.Route, Position{pos.X, height - 1 - pos.Y})
+	}
+	grid.Enemies = append(grid.Enemies, patrol)
+
+	return nil
+}
+
+// Waypoints of the enemies counting the lines from the top, to add them again
+// to a copy of the grid
+func (grid *Grid) enemy_waypoints() [][]Position {
+	var enemies [][]Position
+	for _, enemy := range grid.Enemies {
+		var waypoints []Position
+		for _, waypoint := range enemy.Waypoints {
+			waypoints = append(waypoints, Position{waypoint.X, grid.Height() - 1 - waypoint.Y})
+		}
+		enemies = append(enemies, waypoints)
+	}
+	return enemies
+}
+
+// Check if a player that moved from a position to another on the step meets
+// an enemy, on the same tile or crossing each other
+func (grid *Grid) enemy_hit(from Position, to Position, step int) bool {
+	for _, enemy := range grid.Enemies {
+		if enemy.At(step) == to || (enemy.At(step) == from && enemy.At(step-1) == to) {
+			return true
+		}
+	}
+	return false
+}
+
+// Read the waypoints of an enemy of the map files: "@enemy 3,2 9,2" (column
+// and line of the map, counting from 1 on the top left)
+func parse_enemy(fields []string) ([]Position, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("the enemy needs at least one tile (@enemy <column>,<line> ...)")
+	}
+
+	var waypoints []Position
+	for _, field := range fields {
+		column_text, line_text, _ := strings.Cut(field, ",")
+		column, err_column := strconv.Atoi(column_text)
+		line, err_line := strconv.Atoi(line_text)
+		if err_column != nil || err_line != nil {
+			return nil, fmt.Errorf("invalid tile '%s' of the enemy (<column>,<line>)", field)
+		}
+		waypoints = append(waypoints, Position{column - 1, line - 1})
+	}
+	return waypoints, nil
+}
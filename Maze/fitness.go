@@ -0,0 +1,178 @@
+package Maze
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// --------------------------- Trace ------------------------------ //
+
+// Record of the run of one individual through the maze
+type Trace struct {
+	Path         []Position // Position after each command (Path[0] is the start)
+	Keys         []Keys     // Keys held after each command (nil = no keys)
+	Bumps        int        // Commands that hit a wall (the player didn't move)
+	Reached_step int        // Step when the objective was reached (0 = not reached)
+	Death_step   int        // Step when the player entered a hazard (0 = alive)
+	Commands     int        // Number of commands of the individual
+}
+
+// ------------------------ Fitness Functions --------------------- //
+
+// Evaluate the run of an individual through the maze (bigger is better)
+// Score is called from several goroutines, so it can't change shared state
+type FitnessFunc interface {
+	Score(grid *Grid, trace *Trace) int
+}
+
+// Points lost by the individuals that died for each command they didn't execute
+const death_penalty = 100
+
+// Score of the run with the fitness function, the individuals that died lose
+// points for the commands left, so dying early is worse than dying late
+func run_score(fitness FitnessFunc, grid *Grid, trace *Trace) int {
+	score := fitness.Score(grid, trace)
+	if trace.Death_step > 0 {
+		score -= (trace.Commands - trace.Death_step + 1) * death_penalty
+	}
+	return score
+}
+
+// Built-in fitness functions, selected by the INI file ([Settings] Fitness)
+var fitness_functions = map[string]FitnessFunc{
+	"column":   ColumnFitness{},
+	"distance": DistanceFitness{},
+	"steps":    StepsFitness{Reach_bonus: 1000},
+	"bump":     BumpFitness{Penalty: 50},
+	"coverage": CoverageFitness{},
+}
+
+// Find a built-in fitness function by its name
+func NewFitness(name string) (FitnessFunc, error) {
+	fitness, ok := fitness_functions[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("fitness function '%s' not found (options: %s)", name, strings.Join(FitnessNames(), ", "))
+	}
+	return fitness, nil
+}
+
+// Names of the built-in fitness functions
+func FitnessNames() []string {
+	var names []string
+	for name := range fitness_functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Keys held after the step
+func (trace *Trace) keys(step int) Keys {
+	if step >= len(trace.Keys) {
+		return 0
+	}
+	return trace.Keys[step]
+}
+
+// Closest distance to the exit reached during the run, -1 if there isn't a route
+// The distance counts the detours to get the keys that weren't picked up yet
+func closest_distance(grid *Grid, trace *Trace) int {
+	closest := -1
+	for step, pos := range trace.Path {
+		distance := grid.Distance(pos.X, pos.Y, trace.keys(step))
+		if distance >= 0 && (closest == -1 || distance < closest) {
+			closest = distance
+		}
+	}
+	return closest
+}
+
+// Steps needed to get closer to the exit (100 points for each step)
+func distance_progress(grid *Grid, trace *Trace) int {
+	start := grid.Distance(trace.Path[0].X, trace.Path[0].Y, trace.keys(0))
+	closest := closest_distance(grid, trace)
+	if start == -1 || closest == -1 {
+		return 0
+	}
+	return (start - closest) * 100
+}
+
+// --------------------------- Column ----------------------------- //
+
+// Original score: each time the player reaches a new column to the right it
+// receives points, more points when the column is reached with less commands
+// (max_pos / cycles_needed * 100)
+type ColumnFitness struct{}
+
+func (ColumnFitness) Score(grid *Grid, trace *Trace) int {
+	var (
+		score            int
+		max_ind_position int
+		last_jump_cycle  int
+	)
+
+	for cycle := 1; cycle < len(trace.Path); cycle++ {
+		if trace.Path[cycle].X > max_ind_position {
+			max_ind_position = trace.Path[cycle].X
+			cycles_needed := cycle - last_jump_cycle
+
+			score += int(math.Round((float64(max_ind_position) / float64(cycles_needed)) * 100))
+
+			// Update the cycle of last jump (for next avaliation)
+			last_jump_cycle = cycle
+		}
+	}
+
+	return score
+}
+
+// -------------------------- Distance ---------------------------- //
+
+// Shortest route (breadth-first search) to the exit: points for each step
+// closer to the exit, even when the route has to move left or backtrack
+type DistanceFitness struct{}
+
+func (DistanceFitness) Score(grid *Grid, trace *Trace) int {
+	return distance_progress(grid, trace)
+}
+
+// ---------------------------- Steps ----------------------------- //
+
+// Individuals that reach the exit receive a bonus plus points for each command
+// left, the others are ranked by the distance to the exit
+type StepsFitness struct {
+	Reach_bonus int
+}
+
+func (fitness StepsFitness) Score(grid *Grid, trace *Trace) int {
+	if trace.Reached_step > 0 {
+		return fitness.Reach_bonus + (trace.Commands-trace.Reached_step)*100
+	}
+	return distance_progress(grid, trace) / 10
+}
+
+// ---------------------------- Bump ------------------------------ //
+
+// Distance to the exit, with a penalty for each command that hits a wall
+type BumpFitness struct {
+	Penalty int
+}
+
+func (fitness BumpFitness) Score(grid *Grid, trace *Trace) int {
+	return distance_progress(grid, trace) - trace.Bumps*fitness.Penalty
+}
+
+// -------------------------- Coverage ---------------------------- //
+
+// Number of different cells visited (100 points each), rewarding exploration
+type CoverageFitness struct{}
+
+func (CoverageFitness) Score(grid *Grid, trace *Trace) int {
+	visited := make(map[Position]bool)
+	for _, pos := range trace.Path {
+		visited[pos] = true
+	}
+	return len(visited) * 100
+}
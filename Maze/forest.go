@@ -0,0 +1,159 @@
+package Maze
+
+import (
+	"math/rand"
+)
+
+// ------------------------ Forest Generator ---------------------- //
+
+// Open field with clumps of trees (like the maps 1 and 3), created by a
+// cellular automaton: each tile starts as a tree with the chance Density, then
+// on each iteration a path with at least Birth neighbour trees (of 8) becomes a
+// tree, and a tree with less than Survival neighbour trees becomes a path.
+// The border is a line of trees with the start on the left and the exit on the
+// right, and the trees that block all routes between them are removed
+type ForestGenerator struct {
+	Density    float64
+	Birth      int
+	Survival   int
+	Iterations int
+}
+
+func (g ForestGenerator) Generate(rng *rand.Rand, width int, height int) (*Grid, error) {
+
+	// Trees of the automaton (lines counted from the top), the border is always a tree
+	trees := make([][]bool, height)
+	for line := range trees {
+		trees[line] = make([]bool, width)
+		for column := range trees[line] {
+			border := line == 0 || line == height-1 || column == 0 || column == width-1
+			trees[line][column] = border || rng.Float64() < g.Density
+		}
+	}
+
+	for i := 0; i < g.Iterations; i++ {
+		trees = g.iterate(trees)
+	}
+
+	// Open the borders and the tiles next to them
+	start := Position{0, rng.Intn(height-2) + 1}
+	exit := Position{width - 1, rng.Intn(height-2) + 1}
+	trees[start.Y][0], trees[start.Y][1] = false, false
+	trees[exit.Y][width-1], trees[exit.Y][width-2] = false, false
+
+	connect_forest(trees, start, exit)
+
+	// Border with light green trees, the clumps with all kinds of trees
+	tiles := make([][]uint8, height)
+	for line := range tiles {
+		tiles[line] = make([]uint8, width)
+		for column := range tiles[line] {
+			if !trees[line][column] {
+				continue
+			}
+			if line == 0 || line == height-1 || column == 0 || column == width-1 {
+				tiles[line][column] = 1
+			} else {
+				tiles[line][column] = uint8(rng.Intn(4) + 1)
+			}
+		}
+	}
+
+	return new_grid(tiles, start, []Position{exit})
+}
+
+// One step of the cellular automaton (the border doesn't change)
+func (g ForestGenerator) iterate(trees [][]bool) [][]bool {
+	height, width := len(trees), len(trees[0])
+
+	next := make([][]bool, height)
+	for line := range next {
+		next[line] = make([]bool, width)
+		for column := range next[line] {
+			if line == 0 || line == height-1 || column == 0 || column == width-1 {
+				next[line][column] = true
+				continue
+			}
+
+			neighbours := 0
+			for l := line - 1; l <= line+1; l++ {
+				for c := column - 1; c <= column+1; c++ {
+					if (l != line || c != column) && trees[l][c] {
+						neighbours++
+					}
+				}
+			}
+
+			if trees[line][column] {
+				next[line][column] = neighbours >= g.Survival
+			} else {
+				next[line][column] = neighbours >= g.Birth
+			}
+		}
+	}
+	return next
+}
+
+// Remove the trees of the route from the start to the exit that passes
+// through less trees (breadth-first search where each tree costs 1 step),
+// so the forest always has a route and the clumps are cut as little as possible
+func connect_forest(trees [][]bool, start Position, exit Position) {
+	height, width := len(trees), len(trees[0])
+
+	cost := make([][]int, height)
+	previous := make([][]Position, height)
+	for line := range cost {
+		cost[line] = make([]int, width)
+		previous[line] = make([]Position, width)
+		for column := range cost[line] {
+			cost[line][column] = -1
+		}
+	}
+
+	// Positions here count the lines from the top, like the trees
+	// Each level has the tiles reached passing through the same number of trees
+	cost[start.Y][start.X] = 0
+	level := []Position{start}
+	for trees_cut := 0; len(level) > 0; trees_cut++ {
+		var next_level []Position
+
+		for i := 0; i < len(level); i++ {
+			pos := level[i]
+			if cost[pos.Y][pos.X] != trees_cut {
+				continue // Reached later through less trees
+			}
+
+			for direction := up; direction <= right; direction++ {
+				next := pos.move(direction)
+
+				// Just the inside of the forest, besides the exit
+				inside := next.X > 0 && next.X < width-1 && next.Y > 0 && next.Y < height-1
+				if !inside && next != exit {
+					continue
+				}
+
+				next_cost := trees_cut
+				if trees[next.Y][next.X] {
+					next_cost++
+				}
+				if cost[next.Y][next.X] >= 0 && cost[next.Y][next.X] <= next_cost {
+					continue
+				}
+				cost[next.Y][next.X] = next_cost
+				previous[next.Y][next.X] = pos
+
+				// Free tiles stay on the same level
+				if next_cost == trees_cut {
+					level = append(level, next)
+				} else {
+					next_level = append(next_level, next)
+				}
+			}
+		}
+		level = next_level
+	}
+
+	for pos := exit; pos != start; pos = previous[pos.Y][pos.X] {
+		trees[pos.Y][pos.X] = false
+	}
+}
@@ -3,10 +3,10 @@ package Maze
 import (
 	"fmt"
 	"image"
+	"image/color"
 	_ "image/png"
 	"math"
 	"os"
-	"strings"
 
 	"github.com/faiface/pixel"
 	"github.com/faiface/pixel/imdraw"
@@ -17,17 +17,15 @@ import (
 )
 
 // ----------- Player ----------- //
-type Direction int
+type Direction uint8
 
 type player struct {
-	sprites                map[Direction][]pixel.Rect
-	currentSprite          pixel.Rect
-	spriteMap              pixel.Picture
-	grid_pos_X             int
-	grid_pos_Y             int
-	score                  int
-	max_ind_position       int
-	max_ind_position_cycle int
+	sprites       map[Direction][]pixel.Rect
+	currentSprite pixel.Rect
+	grid_pos_X    int
+	grid_pos_Y    int
+	keys          Keys  // Keys picked up, they open the doors of the same color
+	trace         Trace // Record of the run, evaluated by the fitness function
 }
 
 // --------- Background --------- //
@@ -42,20 +40,15 @@ type block struct {
 	gridY         int
 }
 
-// ---------- Objective --------- //
-type objective_reached struct {
-	generation int
-	individual string
-	score      int
-	steps      int
-}
-
 // ---------- Constants --------- //
 const (
 	// Window Size
 	screen_height = 800
 	screen_width  = 800
 
+	// The debug screen (automation) uses the top of the window, from this line
+	debug_screen_bottom = 630
+
 	// Directions
 	up    Direction = 0
 	down  Direction = 1
@@ -65,37 +58,6 @@ const (
 
 // --------- Variables ---------- //
 var (
-	// IA
-	Automation      bool = false
-	commands_matrix [][]Direction
-
-	// Slice of players
-	player_list []*player
-	direction   Direction
-
-	// Background
-	backgroundMap [][]uint8
-	// Grid size is defined by : X = number of objects per line	Y = number of objects in the slice
-	grid_size_x       int
-	grid_size_y       int
-	map_best_solution int
-
-	// Score
-	max_generation_position int = 0
-
-	// Keyboard
-	keyboard_human       map[Direction][]bool // Human Keyboard
-	keyboard_automations map[Direction][]bool // Automation Virtual Keyboards
-
-	// Cycle counter
-	cycle int = 0
-
-	// Objective slice
-	objective []objective_reached
-
-	// Simulation finish and show results
-	simlation_finished bool = false
-
 	// Fonts
 	atlas = text.NewAtlas(basicfont.Face7x13, text.ASCII)
 
@@ -132,6 +94,14 @@ func setSprite(spriteWidth float64, spriteHeight float64, posX int, posY int) pi
 	)
 }
 
+// Height of the window used by the board, the debug screen (automation) is drawn over it
+func boardHeight(automation bool) float64 {
+	if automation {
+		return debug_screen_bottom
+	}
+	return screen_height
+}
+
 // Get the coordinates to draw objects on screen
 func getObjectGridPosition(width float64, height float64, grid_x_size int, grid_y_size int, x int, y int) pixel.Rect {
 	// gridWidth := width / float64(grid_x_size)
@@ -141,11 +111,176 @@ func getObjectGridPosition(width float64, height float64, grid_x_size int, grid_
 	return pixel.R(float64(x)*gridWidth, float64(y)*gridHeight, float64((x+1))*gridWidth, float64((y+1))*gridHeight)
 }
 
+// Draw the shortest route from the start to an exit over the board
+func draw_shortest_path(imd *imdraw.IMDraw, grid *Grid, path []Position, height float64) {
+	imd.Color = colornames.Orange
+
+	for _, pos := range path {
+		cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), pos.X, pos.Y)
+		imd.Push(cell.Center())
+	}
+	imd.Line(3)
+
+	for _, pos := range path {
+		cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), pos.X, pos.Y)
+		imd.Push(cell.Center())
+		imd.Circle(cell.H()/8, 0)
+	}
+}
+
+// Colors of the keys and their doors (a - d)
+var key_palette = [key_colors]color.RGBA{colornames.Gold, colornames.Crimson, colornames.Royalblue, colornames.Mediumorchid}
+
+// Draw the keys and doors over the board, the keys held aren't drawn and
+// their doors are drawn open (just the frame)
+func draw_keys_doors(imd *imdraw.IMDraw, grid *Grid, keys Keys, height float64) {
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), x, y)
+			size := math.Min(cell.W(), cell.H())
+
+			if color, ok := key_color(grid.Tile(x, y)); ok && !keys.Has(color) {
+				// Ring, blade and tooth of the key
+				center := cell.Center()
+				imd.Color = key_palette[color]
+				imd.Push(center.Add(pixel.V(-size/5, 0)))
+				imd.Circle(size/8, size/20)
+				imd.Push(center.Add(pixel.V(-size/12, 0)), center.Add(pixel.V(size/3, 0)))
+				imd.Line(size / 16)
+				imd.Push(center.Add(pixel.V(size/4, 0)), center.Add(pixel.V(size/4, -size/8)))
+				imd.Line(size / 16)
+			}
+
+			if color, ok := door_color(grid.Tile(x, y)); ok {
+				imd.Color = key_palette[color]
+				imd.Push(cell.Min.Add(pixel.V(1, 1)), cell.Max.Sub(pixel.V(1, 1)))
+				if keys.Has(color) {
+					imd.Rectangle(size / 10)
+				} else {
+					imd.Rectangle(0)
+				}
+			}
+		}
+	}
+}
+
+// Colors of the hazards (water, lava and spikes)
+var hazard_palette = [hazard_kinds]color.RGBA{colornames.Deepskyblue, colornames.Orangered, colornames.Dimgray}
+
+// Draw the hazards over the board: water with waves, lava with bubbles and spikes
+func draw_hazards(imd *imdraw.IMDraw, grid *Grid, height float64) {
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			tile := grid.Tile(x, y)
+			if !is_hazard(tile) {
+				continue
+			}
+			cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), x, y)
+			size := math.Min(cell.W(), cell.H())
+			point := func(x float64, y float64) pixel.Vec {
+				return pixel.V(cell.Min.X+cell.W()*x, cell.Min.Y+cell.H()*y)
+			}
+
+			imd.Color = hazard_palette[tile-hazard_tile]
+			imd.Push(cell.Min, cell.Max)
+			imd.Rectangle(0)
+
+			switch tile - hazard_tile {
+			case 0:
+				imd.Color = colornames.White
+				for _, wave := range []float64{0.3, 0.6} {
+					imd.Push(point(0.15, wave), point(0.4, wave+0.1), point(0.6, wave), point(0.85, wave+0.1))
+					imd.Line(size / 20)
+				}
+			case 1:
+				imd.Color = colornames.Gold
+				imd.Push(point(0.3, 0.35), point(0.7, 0.65))
+				imd.Circle(size/10, 0)
+			case 2:
+				imd.Color = colornames.Silver
+				for spike := 0.0; spike < 3; spike++ {
+					imd.Push(point(0.1+spike*0.27, 0.15), point(0.37+spike*0.27, 0.15), point(0.235+spike*0.27, 0.85))
+					imd.Polygon(0)
+				}
+			}
+		}
+	}
+}
+
+// Colors of the teleporter pairs (w - z)
+var teleporter_palette = [teleporter_pairs]color.RGBA{colornames.Turquoise, colornames.Hotpink, colornames.Chartreuse, colornames.Darkorange}
+
+// Rotation of the arrows of the one-way tiles, drawn pointing up
+var one_way_angles = [...]float64{up: 0, down: math.Pi, left: math.Pi / 2, right: -math.Pi / 2}
+
+// Draw the teleporters (rings with the color of their pair) and the one-way
+// tiles (arrows to their direction) over the board
+func draw_moves(imd *imdraw.IMDraw, grid *Grid, height float64) {
+	for y := 0; y < grid.Height(); y++ {
+		for x := 0; x < grid.Width(); x++ {
+			cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), x, y)
+			size := math.Min(cell.W(), cell.H())
+			center := cell.Center()
+
+			if pair, ok := teleporter_pair(grid.Tile(x, y)); ok {
+				imd.Color = teleporter_palette[pair]
+				imd.Push(center)
+				imd.Circle(size*0.4, size/12)
+				imd.Push(center)
+				imd.Circle(size*0.2, 0)
+			}
+
+			if direction, ok := one_way_direction(grid.Tile(x, y)); ok {
+				point := func(x float64, y float64) pixel.Vec {
+					return center.Add(pixel.V(x*size, y*size).Rotated(one_way_angles[direction]))
+				}
+				imd.Color = colornames.Tan
+				imd.Push(cell.Min, cell.Max)
+				imd.Rectangle(0)
+				imd.Color = colornames.Saddlebrown
+				imd.Push(point(0, 0.35), point(-0.3, 0.05), point(0.3, 0.05))
+				imd.Polygon(0)
+				imd.Push(point(0, 0.1), point(0, -0.35))
+				imd.Line(size / 8)
+			}
+		}
+	}
+}
+
+// Draw a cross over a dead player
+func draw_dead(imd *imdraw.IMDraw, grid *Grid, pos Position, height float64) {
+	cell := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), pos.X, pos.Y)
+	size := math.Min(cell.W(), cell.H())
+	inside := cell.Resized(cell.Center(), cell.Size().Scaled(0.7))
+
+	imd.Color = colornames.Red
+	imd.Push(inside.Min, inside.Max)
+	imd.Line(size / 10)
+	imd.Push(pixel.V(inside.Min.X, inside.Max.Y), pixel.V(inside.Max.X, inside.Min.Y))
+	imd.Line(size / 10)
+}
+
+// Sprites of the enemies on each direction, the gray character of the player sprites
+var enemy_sprites = map[Direction]pixel.Rect{
+	up:    setSprite(50, 70, 10, 0),
+	down:  setSprite(50, 70, 10, 3),
+	left:  setSprite(50, 70, 10, 2),
+	right: setSprite(50, 70, 10, 1),
+}
+
+// Draw the enemies on their positions of the step, looking to the next tile
+func draw_enemies(t pixel.Target, spriteMap pixel.Picture, grid *Grid, step int, height float64) {
+	for _, enemy := range grid.Enemies {
+		sprite := pixel.NewSprite(spriteMap, enemy_sprites[enemy.facing(step)])
+		pos := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), enemy.At(step).X, enemy.At(step).Y)
+		sprite.Draw(t, pixel.IM.ScaledXY(pixel.ZV, pixel.V(pos.W()/sprite.Frame().W(), pos.H()/sprite.Frame().H())).Moved(pos.Center()))
+	}
+}
+
 // ---------------------------- Player ---------------------------- //
 
 // Set player sprites in a map based on its direction
-func (object *player) setPlayerSprites(spriteMapImg pixel.Picture) {
-	object.spriteMap = spriteMapImg
+func (object *player) setPlayerSprites() {
 	// X,Y(Size of each pixel), X, Y(Position in spriteMap)
 	object.sprites = make(map[Direction][]pixel.Rect)
 	object.sprites[up] = append(object.sprites[up], setSprite(50, 70, 6, 0))
@@ -156,110 +291,101 @@ func (object *player) setPlayerSprites(spriteMapImg pixel.Picture) {
 
 // Draw Player on screen
 // func (p0 *player) draw(win pixel.Target) {
-func (object *player) draw(win pixel.Target) {
+func (object *player) draw(win pixel.Target, spriteMap pixel.Picture, grid *Grid, height float64) {
 	sprite := pixel.NewSprite(nil, pixel.Rect{})
-	sprite.Set(object.spriteMap, object.currentSprite)
-	pos := getObjectGridPosition(screen_width, screen_height, grid_size_x, grid_size_y, object.grid_pos_X, object.grid_pos_Y)
+	sprite.Set(spriteMap, object.currentSprite)
+	pos := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), object.grid_pos_X, object.grid_pos_Y)
 	sprite.Draw(win, pixel.IM.ScaledXY(pixel.ZV, pixel.V(pos.W()/sprite.Frame().W(), pos.H()/sprite.Frame().H())).Moved(pos.Center()))
 }
 
 // Update the grid position accordingly to the direction of the next frame
-// Collision Detection
-func (object *player) getNewGridPos(direction Direction) (int, int) {
-	if direction == right {
-		// Keep the player inside the window && just update if there isn't an object on the next move position
-		if object.grid_pos_X+1 < grid_size_x && backgroundMap[len(backgroundMap)-1-object.grid_pos_Y][object.grid_pos_X+1] == 0 {
-			object.grid_pos_X += 1
-		}
-		return object.grid_pos_X, object.grid_pos_Y
-	}
-	// backgroundMap[line][column]
-	if direction == left {
-		// Keep the player inside the window && just update if there isn't an object on the next move position
-		if object.grid_pos_X-1 >= 0 && backgroundMap[len(backgroundMap)-1-object.grid_pos_Y][object.grid_pos_X-1] == 0 {
-			object.grid_pos_X -= 1
-		}
-		return object.grid_pos_X, object.grid_pos_Y
-	}
-	if direction == up {
-		// Keep the player inside the window && just update if there isn't an object on the next move position
-		if object.grid_pos_Y+1 < grid_size_y && backgroundMap[len(backgroundMap)-1-(object.grid_pos_Y+1)][object.grid_pos_X] == 0 {
-			object.grid_pos_Y += 1
-		}
-		return object.grid_pos_X, object.grid_pos_Y
-	}
-	if direction == down {
-		// Keep the player inside the window && just update if there isn't an object on the next move position
-		if object.grid_pos_Y-1 >= 0 && backgroundMap[len(backgroundMap)-1-(object.grid_pos_Y-1)][object.grid_pos_X] == 0 {
-			object.grid_pos_Y -= 1
-		}
-		return object.grid_pos_X, object.grid_pos_Y
+// Collision Detection, with the rules of the one-way tiles and teleporters (step)
+func (object *player) getNewGridPos(grid *Grid, direction Direction) (int, int) {
+	// Keep the player inside the window && just update if there isn't an object on the next move position
+	if next, ok := grid.step(Position{object.grid_pos_X, object.grid_pos_Y}, direction, object.keys); ok {
+		object.grid_pos_X, object.grid_pos_Y = next.X, next.Y
 	}
 	return object.grid_pos_X, object.grid_pos_Y
 }
 
 // Update the direction, position on grid and the current sprite each frame
-func (object *player) update(direction Direction, player_index int) {
-	// Update grid positiom
-	object.grid_pos_X, object.grid_pos_Y = object.getNewGridPos(direction)
+func (object *player) update(sim *Simulation, direction Direction, player_index int) {
 
-	// Update current sprite based on direction
-	object.currentSprite = object.sprites[direction][0]
+	// The moves of the human aren't counted by the cycles
+	step := sim.cycle
+	if !sim.Config.Automation {
+		step = len(object.trace.Path)
+	}
+	object.move(sim.Grid, direction, step, sim.Config.Enemy_collision)
 
 	// Test if its new generation record:
-	if object.grid_pos_X > max_generation_position {
-		max_generation_position = object.grid_pos_X
+	sim.record_distance(Position{object.grid_pos_X, object.grid_pos_Y}, object.keys)
+
+	// Objective reached!!
+	if object.trace.Reached_step == sim.cycle && sim.Config.Automation {
+		sim.objective = append(sim.objective, Result{Generation: sim.current_generation, Individual: sim.Population[player_index].Clone(), Exit: Position{object.grid_pos_X, object.grid_pos_Y}, Steps: sim.cycle})
 	}
+}
 
-	// Update the Individual Maximum score and check the objective!
-	if object.grid_pos_X > object.max_ind_position {
+// Move the player and record the run for the fitness function. The enemies
+// caught the player (collision: kill or restart) on the same step
+// Just changes the player, so the players can be moved on different goroutines
+func (object *player) move(grid *Grid, direction Direction, step int, collision string) {
+	previous_X, previous_Y := object.grid_pos_X, object.grid_pos_Y
+
+	// The dead don't move until the next generation
+	if object.trace.Death_step > 0 {
+		object.trace.Path = append(object.trace.Path, Position{previous_X, previous_Y})
+		object.trace.Keys = append(object.trace.Keys, object.keys)
+		return
+	}
 
-		// Update the new maximum posistion of player
-		object.max_ind_position = object.grid_pos_X
+	// Update grid positiom
+	object.grid_pos_X, object.grid_pos_Y = object.getNewGridPos(grid, direction)
 
-		// Add score points
-		player_score(object.max_ind_position, cycle, player_index)
+	// Update current sprite based on direction
+	object.currentSprite = object.sprites[direction][0]
 
-		// Objective reached!!
-		if object.grid_pos_X == len(backgroundMap[0])-1 {
+	// Pick up the key of the tile
+	object.keys = object.keys.pick(grid.Tile(object.grid_pos_X, object.grid_pos_Y))
 
-			objective = append(objective, objective_reached{generation: current_generation, individual: population[player_index], score: object.grid_pos_X, steps: cycle})
+	if object.grid_pos_X == previous_X && object.grid_pos_Y == previous_Y {
+		object.trace.Bumps++
+	}
 
-			// fmt.Printf("\n\n\n\t\tObjective accomplished!\n\t\tIndividual: %s\tPosition: %d\tMovements: %d\n\n\n", population[player_index], len(backgroundMap[0]) - 1, cycle)
+	// Caught by an enemy, before reaching the exit of the same tile
+	if grid.enemy_hit(Position{previous_X, previous_Y}, Position{object.grid_pos_X, object.grid_pos_Y}, step) {
+		if collision == Enemy_restarts {
+			object.grid_pos_X, object.grid_pos_Y = grid.Start.X, grid.Start.Y
+		} else {
+			object.trace.Death_step = step
 		}
 	}
-	// Punishment
-	// } else {
-	// 	object.score -= 10
-	// }
-
-}
 
-// Calculate the player's score
-func player_score(max_pos int, cycle int, plr_index int) {
-	var (
-		tmp_score     float64
-		cycles_needed int
-	)
+	// Record the run for the fitness function
+	object.trace.Path = append(object.trace.Path, Position{object.grid_pos_X, object.grid_pos_Y})
+	object.trace.Keys = append(object.trace.Keys, object.keys)
 
-	cycles_needed = cycle - player_list[plr_index].max_ind_position_cycle
-	// fmt.Printf("Player %d\tCycle: %d\tMaxPos: %d\tcycles needed: %d\t\n",plr_index, cycle, max_pos, cycles_needed)
-
-	tmp_score = (float64(max_pos) / float64(cycles_needed)) * 100
-	// fmt.Println(tmp_score)
-	player_list[plr_index].score += int(math.Round(tmp_score))
-
-	// fmt.Printf("Individual: %d (%s)\tGeneration:%d\tNew max_pos: %d\tSteps: %d\tNew Score: %d\n",individual_number, population[individual_number], current_generation, max_pos, cycle, score)
+	// Objective reached!!
+	if object.trace.Reached_step == 0 && object.trace.Death_step == 0 && grid.is_exit(Position{object.grid_pos_X, object.grid_pos_Y}) {
+		object.trace.Reached_step = step
+	}
 
-	// Update the cycle of last jump (for next avaliation)
-	player_list[plr_index].max_ind_position_cycle = cycle
+	// Entered a hazard
+	if object.trace.Death_step == 0 && is_hazard(grid.Tile(object.grid_pos_X, object.grid_pos_Y)) {
+		object.trace.Death_step = step
+	}
+}
 
+// Calculate the player's score with the fitness function selected
+func (sim *Simulation) player_score(plr_index int) int {
+	return run_score(sim.Config.Fitness, sim.Grid, &sim.players[plr_index].trace)
 }
 
 // -------------------------- Background -------------------------- //
 
 // Set board sprites in a map based on its direction
-func (bgd *background) setPlayerSprites(spriteMapImg pixel.Picture) {
+func (bgd *background) setPlayerSprites() {
 
 	// X,Y(Size of each pixel), X, Y(Position in spriteMap)
 	bgd.sprites = make(map[int][]pixel.Rect)
@@ -271,10 +397,10 @@ func (bgd *background) setPlayerSprites(spriteMapImg pixel.Picture) {
 }
 
 // Draw a single block of the background
-func (blk block) draw(t pixel.Target) {
+func (blk block) draw(t pixel.Target, grid *Grid, height float64) {
 	sprite := pixel.NewSprite(nil, pixel.Rect{})
 	sprite.Set(blk.spriteMap, blk.currentSprite)
-	pos := getObjectGridPosition(screen_width, screen_height, len(backgroundMap[0]), len(backgroundMap), blk.gridY, blk.gridX)
+	pos := getObjectGridPosition(screen_width, height, grid.Width(), grid.Height(), blk.gridY, blk.gridX)
 
 	sprite.Draw(t, pixel.IM.
 		ScaledXY(pixel.ZV, pixel.V(
@@ -285,101 +411,73 @@ func (blk block) draw(t pixel.Target) {
 	)
 }
 
-// Draw blocks into the background
-func (bgd *background) draw(t pixel.Target) error {
+// Draw blocks into the background (height of the window used by the board)
+func (bgd *background) draw(t pixel.Target, grid *Grid, height float64) error {
+	backgroundMap := grid.Cells
+
 	for i := 0; i < len(backgroundMap); i++ { // Lines
 		for j := 0; j < len(backgroundMap[0]); j++ { // Columns
 			if backgroundMap[i][j] == 0 {
 				// Don't draw anything, its the path
 			} else if backgroundMap[i][j] == 1 {
 				b := block{currentSprite: bgd.sprites[0][0], gridX: (len(backgroundMap) - 1) - i, gridY: j}
-				b.draw(t)
+				b.draw(t, grid, height)
 			} else if backgroundMap[i][j] == 2 {
 				b := block{currentSprite: bgd.sprites[1][0], gridX: (len(backgroundMap) - 1) - i, gridY: j}
-				b.draw(t)
+				b.draw(t, grid, height)
 			} else if backgroundMap[i][j] == 3 {
 				b := block{currentSprite: bgd.sprites[2][0], gridX: (len(backgroundMap) - 1) - i, gridY: j}
-				b.draw(t)
+				b.draw(t, grid, height)
 			} else if backgroundMap[i][j] == 4 {
 				b := block{currentSprite: bgd.sprites[3][0], gridX: (len(backgroundMap) - 1) - i, gridY: j}
-				b.draw(t)
+				b.draw(t, grid, height)
 			} else if backgroundMap[i][j] == 5 {
 				b := block{currentSprite: bgd.sprites[4][0], gridX: (len(backgroundMap) - 1) - i, gridY: j}
-				b.draw(t)
+				b.draw(t, grid, height)
 			}
 		}
 	}
 	return nil
 }
 
-func (*player) restart_player(sprMap pixel.Picture, object *player) {
-	// Initial Position
-	object.grid_pos_X = 0
-	object.grid_pos_Y = 7
+func (*player) restart_player(object *player, start Position) {
+	// Initial Position (start of the map)
+	object.grid_pos_X = start.X
+	object.grid_pos_Y = start.Y
 	// Load the Player Sprites in a map
-	object.setPlayerSprites(sprMap)
+	object.setPlayerSprites()
 	// Initial Direction
-	direction = right // To identify the initial sprite
-	object.currentSprite = object.sprites[direction][0]
-	// Score
-	object.score = 0
-	object.max_ind_position = 0
-	object.max_ind_position_cycle = 0
+	object.currentSprite = object.sprites[right][0] // To identify the initial sprite
+	// Nothing picked up yet
+	object.keys = 0
+	// Restart the record of the run
+	object.trace = Trace{Path: []Position{{object.grid_pos_X, object.grid_pos_Y}}, Keys: []Keys{0}}
 }
 
-// Convert the binary string of individuals to commands
-func individualtoCommands(pop []string, gene_nr int) [][]Direction {
-
-	// -------------- Prepare the Multidimensional Slice -------------- //
-	// Declaring a slice of slices with a length of POPULATION
-	commands := make([][]Direction, len(pop))
-
-	// looping through the slice to declare a slice of each slice size
-	for i := 0; i < len(pop); i++ {
-
-		// Length of each slice shoulg be a half of Gene_number (2 digits = 1 command)
-		new_length := gene_nr / 2
-
-		commands[i] = make([]Direction, new_length)
-	}
-
-	// ----------------------- Process the data ----------------------- //
-
-	// Decode each individual into commands
-	for i := 0; i < len(pop); i++ {
+// ------------------------ PixelGL Window ------------------------ //
+func Run(config Config) {
 
-		individual_split := strings.Split(pop[i], "")
+	// ----------------------- Config ----------------------- //
 
-		// Read individual and transform it to a slice with commands
-		index := 0
-		for j := 0; j < len(individual_split)/2; j++ {
-			code := fmt.Sprintf("%s%s", individual_split[index], individual_split[index+1])
+	win := new_window()
 
-			if code == "00" {
-				commands[i][j] = 0
-			} else if code == "01" {
-				commands[i][j] = 1
-			} else if code == "10" {
-				commands[i][j] = 2
-			} else if code == "11" {
-				commands[i][j] = 3
-			} else {
-				fmt.Printf("Value unexpected on binary to command conversion, exiting.\n")
-				os.Exit(2)
-			}
+	// Disable on screen mouse cursor
+	win.SetCursorVisible(false)
 
-			index += 2
-		}
+	// ------------------------- IA ------------------------- //
 
+	// Generate the population, players and map
+	sim, err := NewSimulation(config)
+	if err != nil {
+		fmt.Printf("\n%s. Exiting\n", err)
+		os.Exit(2)
 	}
-	return commands
-}
 
-// ------------------------ PixelGL Window ------------------------ //
-func Run() {
-
-	// ----------------------- Config ----------------------- //
+	sim.show(win)
+}
 
+// Create the game window
+func new_window() *pixelgl.Window {
 	cfg := pixelgl.WindowConfig{
 		Title:  "Maze Game",
 		Bounds: pixel.R(0, 0, screen_width, screen_height),
@@ -389,102 +487,43 @@ func Run() {
 	if err != nil {
 		panic(err)
 	}
+	return win
+}
 
-	// Disable on screen mouse cursor
-	win.SetCursorVisible(false)
-
-	// ------------------------- IA ------------------------- //
-
-	// Validate parameters
-	validate_parameters(Population_size, K)
-
-	// 0 - Generate the population
-	// Generate each individual for population
-	for i := 0; i < Population_size; i++ {
-		population = append(population, generate_individuals(Gene_number))
-	}
+// Play or evolve the simulation on the window until it's closed or Esc is pressed
+func (sim *Simulation) show(win *pixelgl.Window) {
 
 	// ---------------------- Keyboard ---------------------- //
 
 	// Keyboard used by human user
-	keyboard_human = make(map[Direction][]bool)
+	keyboard_human := make(map[Direction][]bool)
 	keyboard_human[up] = append(keyboard_human[up], false)
 	keyboard_human[down] = append(keyboard_human[down], false)
 	keyboard_human[left] = append(keyboard_human[left], false)
 	keyboard_human[right] = append(keyboard_human[right], false)
 
-	// Keyboard used by automations
-	keyboard_automations = make(map[Direction][]bool)
-	for i := 0; i < len(population); i++ {
-		keyboard_automations[up] = append(keyboard_automations[up], false)
-		keyboard_automations[down] = append(keyboard_automations[down], false)
-		keyboard_automations[left] = append(keyboard_automations[left], false)
-		keyboard_automations[right] = append(keyboard_automations[right], false)
-	}
-
-	// ------------------- Define the map ------------------- //
-
-	if Automation {
-		if Maze_map == 0 {
-			backgroundMap = backgroundMap_0_automate
-			map_best_solution = backgroundMap_0_best_solution
-		} else if Maze_map == 1 {
-			backgroundMap = backgroundMap_1_automate
-			map_best_solution = backgroundMap_1_best_solution
-		} else if Maze_map == 2 {
-			backgroundMap = backgroundMap_2_automate
-			map_best_solution = backgroundMap_2_best_solution
-		} else if Maze_map == 3 {
-			backgroundMap = backgroundMap_3_automate
-			map_best_solution = backgroundMap_3_best_solution
-		} else {
-			fmt.Printf("Map %d not found! Exiting.\n", Maze_map)
-			os.Exit(2)
-		}
-
-	} else {
-		if Maze_map == 0 {
-			backgroundMap = backgroundMap_0
-			map_best_solution = backgroundMap_0_best_solution
-		} else if Maze_map == 1 {
-			backgroundMap = backgroundMap_1
-			map_best_solution = backgroundMap_1_best_solution
-		} else if Maze_map == 2 {
-			backgroundMap = backgroundMap_2
-			map_best_solution = backgroundMap_2_best_solution
-		} else if Maze_map == 3 {
-			backgroundMap = backgroundMap_3
-			map_best_solution = backgroundMap_3_best_solution
-		} else {
-			fmt.Printf("Map %d not found! Exiting.\n", Maze_map)
-			os.Exit(2)
-		}
-	}
-
-	// Calculate the size of the grid according to map selected
-	grid_size_x = len(backgroundMap[0])
-	grid_size_y = len(backgroundMap)
+	// Shortest route of the map, shown with the P key
+	shortest_path := sim.Grid.ShortestPath()
+	show_path := false
 
 	// ---------------- Player and background --------------- //
 
 	// Load the PixelMap Image
 	spriteMap, err := loadPicture("Images/spritemap-rpg.png")
-
-	// // Initialize Player data
-	if Automation == false { // Draw just player0
-		player_list = append(player_list, &player{})
-		player_list[0].restart_player(spriteMap, player_list[0])
-	} else { // draw all population
-		// Add players accordingly to population
-		for i := 0; i < Population_size; i++ {
-			player_list = append(player_list, &player{})
-			player_list[i].restart_player(spriteMap, player_list[i])
-		}
+	if err != nil {
+		panic(err)
 	}
 
 	// Initialize the background
 	bgd := &background{}
-	bgd.setPlayerSprites(spriteMap)
+	bgd.setPlayerSprites()
+
+	// Deaths are just shown on maps with hazards or enemies
+	deadly := sim.Grid.has_hazards() || len(sim.Grid.Enemies) > 0
+
+	// Camera over the board, moved with WASD (the arrows move the player)
+	cam := new_camera(sim.Grid, boardHeight(sim.Config.Automation))
+	pan_keys := [4]pixelgl.Button{up: pixelgl.KeyW, down: pixelgl.KeyS, left: pixelgl.KeyA, right: pixelgl.KeyD}
 
 	// Infinite loop
 	for !win.Closed() {
@@ -492,6 +531,9 @@ func Run() {
 		// Draw all background objects first to this object and just draw to window one time later
 		imd := imdraw.New(spriteMap)
 
+		// The debug screen is drawn without the camera
+		hud := imdraw.New(nil)
+
 		// Clear Screen
 		win.Clear(colornames.Lightgreen)
 
@@ -500,101 +542,23 @@ func Run() {
 			break
 		}
 
-		if !simlation_finished {
-
-			// ---------------------- Keyboard ---------------------- //
-
-			// Update player direction and keys pressed
-			if win.JustPressed(pixelgl.KeyUp) {
-				keyboard_human[up][0] = true
-			}
-			if win.JustPressed(pixelgl.KeyDown) {
-				keyboard_human[down][0] = true
-			}
-			if win.JustPressed(pixelgl.KeyLeft) {
-				keyboard_human[left][0] = true
-			}
-			if win.JustPressed(pixelgl.KeyRight) {
-				keyboard_human[right][0] = true
-			}
-
-			// ---------- Read and execute commands from IA ---------- //
-			if Automation {
-
-				// Decode all individuals into commands and save it to a Matrix
-				if cycle == 0 {
-					commands_matrix = individualtoCommands(population, Gene_number)
-				}
-
-				// Loop for all commands available
-				if cycle < len(commands_matrix[0]) {
-
-					// Fill the commands in all virtual keyboards
-					for i := 0; i < len(population); i++ {
-						// Execute the command on keyboard
-
-						// UP[0] first player, UP[1] second player...
-						keyboard_automations[commands_matrix[i][cycle]][i] = true
-					}
+		// P to show or hide the shortest route
+		if win.JustPressed(pixelgl.KeyP) {
+			show_path = !show_path
+		}
 
-					// Update cycle
-					cycle++
+		// R to play again from the start (human)
+		if !sim.Config.Automation && win.JustPressed(pixelgl.KeyR) {
+			sim.players[0].restart_player(sim.players[0], sim.Grid.Start)
+		}
 
-					// Finished all commands for this generation, reset and start again
-				} else {
+		// Zoom and move the camera
+		cam.control(win, pan_keys)
 
-					// If there are more generations to run
-					if current_generation < Generations {
-
-						// Update the Score slice
-						for i := 0; i < Population_size; i++ {
-							population_score = append(population_score, player_list[i].score)
-						}
-
-						// Clean variables for the next generation
-						cycle = 0
-						// // Restart game for next individual
-						for i := 0; i < Population_size; i++ {
-							player_list[i].restart_player(spriteMap, player_list[i])
-						}
-
-						genetic_algorithm()
-						current_generation++
-						max_generation_position = 0
-					} else {
-						fmt.Printf("\n\n\n|| ---------------------------------- Simulation Ended ---------------------------------- ||\n\nWinners:\n")
-						for i := 0; i < len(objective); i++ {
-							fmt.Printf("%d\tGen: %d\tIndividual: %s\tScore: %d\tSteps: %d\n", i+1, objective[i].generation, objective[i].individual, objective[i].score, objective[i].steps)
-						}
-
-						// Calculate the best one (less steps)
-						quickest := Gene_number / 2
-						for i := 0; i < len(objective); i++ {
-							if objective[i].steps < quickest {
-								quickest = objective[i].steps
-							}
-						}
-
-						fmt.Printf("\nBest performances:\n")
-						for i := 0; i < len(objective); i++ {
-							if objective[i].steps == quickest {
-								fmt.Printf("Gen: %d\tIndividual: %s\tScore: %d\tSteps: %d\n", objective[i].generation, objective[i].individual, objective[i].score, objective[i].steps)
-							}
-						}
-						fmt.Println()
-
-						// Disable automation
-						Automation = false
-
-						// Show Results
-						simlation_finished = true
-					}
-				}
-			}
+		if !sim.Finished() {
 
 			// ---------------------- Keyboard ---------------------- //
 
-			// Move Player - Necessary for the automation of player execution
 			// Update player direction and keys pressed
 			if win.JustPressed(pixelgl.KeyUp) {
 				keyboard_human[up][0] = true
@@ -611,41 +575,16 @@ func Run() {
 
 			// Move Player - Necessary for the automation of player execution
 			if keyboard_human[up][0] == true {
-				direction = up
-				player_list[0].update(up, 0)
+				sim.players[0].update(sim, up, 0)
 			}
 			if keyboard_human[down][0] == true {
-				direction = down
-				player_list[0].update(down, 0)
+				sim.players[0].update(sim, down, 0)
 			}
 			if keyboard_human[left][0] == true {
-				direction = left
-				player_list[0].update(left, 0)
+				sim.players[0].update(sim, left, 0)
 			}
 			if keyboard_human[right][0] == true {
-				direction = right
-				player_list[0].update(right, 0)
-			}
-
-			// Virtual Keyboard for automation
-			// Move Automated Players - Necessary for the automation of player execution
-			for i := 0; i < len(population); i++ {
-				if keyboard_automations[up][i] == true {
-					direction = up
-					player_list[i].update(up, i)
-				}
-				if keyboard_automations[down][i] == true {
-					direction = down
-					player_list[i].update(down, i)
-				}
-				if keyboard_automations[left][i] == true {
-					direction = left
-					player_list[i].update(left, i)
-				}
-				if keyboard_automations[right][i] == true {
-					direction = right
-					player_list[i].update(right, i)
-				}
+				sim.players[0].update(sim, right, 0)
 			}
 
 			// Clean key pressed for the next cycle
@@ -654,11 +593,9 @@ func Run() {
 			keyboard_human[left][0] = false
 			keyboard_human[right][0] = false
 
-			for i := 0; i < len(population); i++ {
-				keyboard_automations[up][i] = false
-				keyboard_automations[down][i] = false
-				keyboard_automations[left][i] = false
-				keyboard_automations[right][i] = false
+			// ---------- Read and execute commands from IA ---------- //
+			if sim.Config.Automation {
+				sim.Step()
 			}
 
 			// // ------------------- Draw Background ------------------ //
@@ -686,119 +623,212 @@ func Run() {
 			// -------------------- Draw Objects -------------------- //
 
 			// Just draw Degug screen if Automation is enabled
-			if Automation {
-				imd.Color = colornames.Gray
-				imd.Push(pixel.V(0, 630))
-				imd.Push(pixel.V(800, 800))
-				imd.Rectangle(0)
+			if sim.Config.Automation {
+				hud.Color = colornames.Gray
+				hud.Push(pixel.V(0, debug_screen_bottom))
+				hud.Push(pixel.V(800, 800))
+				hud.Rectangle(0)
+
+				hud.Color = colornames.Whitesmoke
+				hud.Push(pixel.V(5, debug_screen_bottom+5))
+				hud.Push(pixel.V(795, 795))
+				hud.Rectangle(0)
+			}
 
-				imd.Color = colornames.Whitesmoke
-				imd.Push(pixel.V(5, 635))
-				imd.Push(pixel.V(795, 795))
-				imd.Rectangle(0)
+			// Keep the player (or the best individual) on the view
+			if cam.follow {
+				cam.look_at(sim.Grid, sim.leading_position())
+			}
+
+			// Draw the entire background (below the debug screen), the player
+			// sees the keys picked up and the doors open
+			height := boardHeight(sim.Config.Automation)
+			bgd.draw(imd, sim.Grid, height)
+			if sim.Config.Automation {
+				draw_keys_doors(imd, sim.Grid, 0, height)
+			} else {
+				draw_keys_doors(imd, sim.Grid, sim.players[0].keys, height)
+			}
+			draw_hazards(imd, sim.Grid, height)
+			draw_moves(imd, sim.Grid, height)
+			if show_path {
+				draw_shortest_path(imd, sim.Grid, shortest_path, height)
 			}
 
-			// Draw the entire background
-			bgd.draw(imd)
+			// The enemies walk with the cycles, or with the moves of the human
+			if sim.Config.Automation {
+				draw_enemies(imd, spriteMap, sim.Grid, sim.cycle, height)
+			} else {
+				draw_enemies(imd, spriteMap, sim.Grid, len(sim.players[0].trace.Path)-1, height)
+			}
 
-			// Draw Players on the screen
-			for j := 0; j < len(player_list); j++ {
-				player_list[j].draw(imd)
+			// Draw Players on the screen (the dead with a cross)
+			for j := 0; j < len(sim.players); j++ {
+				sim.players[j].draw(imd, spriteMap, sim.Grid, height)
+				if sim.players[j].trace.Death_step > 0 {
+					draw_dead(imd, sim.Grid, Position{sim.players[j].grid_pos_X, sim.players[j].grid_pos_Y}, height)
+				}
 			}
 
-			// Draw with just one draw() call to screen
+			// Draw with just one draw() call to screen (the board with the camera)
+			win.SetMatrix(cam.matrix())
 			imd.Draw(win)
+			win.SetMatrix(pixel.IM)
+			hud.Draw(win)
+
+			// The human player died on a hazard or was caught by an enemy
+			if !sim.Config.Automation && sim.players[0].trace.Death_step > 0 {
+				textMessage = text.New(pixel.V(20, 780), atlas)
+				textMessage.Clear()
+				textMessage.Color = colornames.Red
+				fmt.Fprintf(textMessage, "You died! Press R to try again")
+				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 2))
+			}
 
 			// Just draw Degug Text Information if Automation is enabled
-			if Automation {
+			if sim.Config.Automation {
+				stats := sim.Stats()
+
 				// Generation
 				textMessage = text.New(pixel.V(20, 780), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "GENERATION: %d of %d", print_current_generation+1, Generations)
+				fmt.Fprintf(textMessage, "GENERATION: %d of %d", stats.Generation+1, sim.Config.Generations)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Mutated individuals
 				textMessage = text.New(pixel.V(20, 760), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Mutated individuals: %d", mutation_ind_count)
+				fmt.Fprintf(textMessage, "Mutated individuals: %d", stats.Mutated_individuals)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Mutated genes
 				textMessage = text.New(pixel.V(260, 760), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Mutated genes: %d", mutation_count)
+				fmt.Fprintf(textMessage, "Mutated genes: %d", stats.Mutated_genes)
+				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+				// Mutation rate (changed by the adaptive mutation) and operators
+				textMessage = text.New(pixel.V(420, 760), atlas)
+				textMessage.Clear()
+				textMessage.Color = colornames.Black
+				fmt.Fprintf(textMessage, "Rate: %.3f", stats.Mutation_rate)
+				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+				textMessage = text.New(pixel.V(260, 740), atlas)
+				textMessage.Clear()
+				textMessage.Color = colornames.Black
+				fmt.Fprintf(textMessage, "Mix: %s", stats.Mutation_mix)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Crossovers
 				textMessage = text.New(pixel.V(20, 740), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Crossovers: %d", print_crossover_count)
+				fmt.Fprintf(textMessage, "Crossovers: %d", stats.Crossovers)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Best Individual
 				textMessage = text.New(pixel.V(20, 720), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Best Individual: %s", print_best)
+				fmt.Fprintf(textMessage, "Best Individual: %s", stats.Best)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Fitness Average
 				textMessage = text.New(pixel.V(20, 700), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Fitness Average: %d", print_average_score)
+				fmt.Fprintf(textMessage, "Fitness Average: %d", stats.Average_score)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
-				// Maximum position
+				// Mutated commands (command operators of the mix)
+				textMessage = text.New(pixel.V(260, 700), atlas)
+				textMessage.Clear()
+				textMessage.Color = colornames.Black
+				fmt.Fprintf(textMessage, "Mutated commands: %d", stats.Mutated_commands)
+				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+				// Camera
+				textMessage = text.New(pixel.V(420, 780), atlas)
+				textMessage.Clear()
+				textMessage.Color = colornames.Black
+				if cam.follow {
+					fmt.Fprintf(textMessage, "Zoom: %.1fx (following the best)", cam.zoom)
+				} else {
+					fmt.Fprintf(textMessage, "Zoom: %.1fx", cam.zoom)
+				}
+				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+				// Closest distance to the exit
 				textMessage = text.New(pixel.V(20, 660), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Maximum position: %d of %d", print_max_generation_position+1, grid_size_x)
+				fmt.Fprintf(textMessage, "Distance to exit: %d", stats.Closest_distance)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 				// Fitness
 				textMessage = text.New(pixel.V(260, 660), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Fitness: %d", print_score)
+				fmt.Fprintf(textMessage, "Fitness: %d", stats.Best_score)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
+				// Individuals dead on the hazards or caught by the enemies
+				if deadly {
+					textMessage = text.New(pixel.V(420, 660), atlas)
+					textMessage.Clear()
+					textMessage.Color = colornames.Black
+					fmt.Fprintf(textMessage, "Deaths: %d (last generation: %d)", sim.deaths(), stats.Deaths)
+					textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+				}
+
 				// Number of Winners
-				if len(objective) > 0 {
+				if len(sim.Results()) > 0 {
 					textMessage = text.New(pixel.V(20, 640), atlas)
 					textMessage.Clear()
 					textMessage.Color = colornames.Black
-					fmt.Fprintf(textMessage, "Number of Winners: %d", len(objective))
+					fmt.Fprintf(textMessage, "Number of Winners: %d", len(sim.Results()))
 					textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 				}
 			}
 
 		} else {
 
-			imd.Color = colornames.Gray
-			imd.Push(pixel.V(0, 630))
-			imd.Push(pixel.V(800, 800))
-			imd.Rectangle(0)
-
-			imd.Color = colornames.Whitesmoke
-			imd.Push(pixel.V(5, 635))
-			imd.Push(pixel.V(795, 795))
-			imd.Rectangle(0)
-
-			// Draw the entire background
-			bgd.draw(imd)
+			hud.Color = colornames.Gray
+			hud.Push(pixel.V(0, debug_screen_bottom))
+			hud.Push(pixel.V(800, 800))
+			hud.Rectangle(0)
+
+			hud.Color = colornames.Whitesmoke
+			hud.Push(pixel.V(5, debug_screen_bottom+5))
+			hud.Push(pixel.V(795, 795))
+			hud.Rectangle(0)
+
+			// Draw the entire background (below the results screen)
+			bgd.draw(imd, sim.Grid, boardHeight(true))
+			draw_keys_doors(imd, sim.Grid, 0, boardHeight(true))
+			draw_hazards(imd, sim.Grid, boardHeight(true))
+			draw_moves(imd, sim.Grid, boardHeight(true))
+			draw_enemies(imd, spriteMap, sim.Grid, 0, boardHeight(true))
+			if show_path {
+				draw_shortest_path(imd, sim.Grid, shortest_path, boardHeight(true))
+			}
 
 			// // Draw Players on the screen
 			// for j := 0; j < len(player_list); j++ {
 			// 	player_list[j].draw(imd)
 			// }
 
-			// Draw with just one draw() call to screen
+			// Draw with just one draw() call to screen (the board with the camera)
+			win.SetMatrix(cam.matrix())
 			imd.Draw(win)
+			win.SetMatrix(pixel.IM)
+			hud.Draw(win)
+
+			stats := sim.Stats()
 
 			// Banner
 			textMessage = text.New(pixel.V(20, 780), atlas)
@@ -811,46 +841,37 @@ func Run() {
 			textMessage = text.New(pixel.V(20, 760), atlas)
 			textMessage.Clear()
 			textMessage.Color = colornames.Black
-			fmt.Fprintf(textMessage, "|| GENERATIONS: %d", print_current_generation+1)
+			fmt.Fprintf(textMessage, "|| GENERATIONS: %d", stats.Generation+1)
+			textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
+
+			// Seed
+			textMessage = text.New(pixel.V(260, 760), atlas)
+			textMessage.Clear()
+			textMessage.Color = colornames.Black
+			fmt.Fprintf(textMessage, "Seed: %d", sim.Config.Seed)
 			textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 			// Number of Winners
 			textMessage = text.New(pixel.V(20, 740), atlas)
 			textMessage.Clear()
 			textMessage.Color = colornames.Black
-			fmt.Fprintf(textMessage, "|| Number of Winners: %d", len(objective))
+			fmt.Fprintf(textMessage, "|| Number of Winners: %d", len(sim.Results()))
 			textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
-			// Maximum position
+			// Closest distance to the exit
 			textMessage = text.New(pixel.V(260, 740), atlas)
 			textMessage.Clear()
 			textMessage.Color = colornames.Black
-			fmt.Fprintf(textMessage, "Maximum position reached: %d of %d", best_step, grid_size_x)
+			fmt.Fprintf(textMessage, "Closest distance to exit: %d", sim.best_distance)
 			textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
-			if len(objective) > 0 {
-
-				// Calculate the best individual (less steps)
-				quickest := Gene_number / 2
-				for i := 0; i < len(objective); i++ {
-					if objective[i].steps < quickest {
-						quickest = objective[i].steps
-					}
-				}
-
-				best_performer := 0 // less steps and the first one to reach the objective
-				for i := len(objective) - 1; i >= 0; i-- {
-					if objective[i].steps == quickest {
-						// fmt.Printf("Gen: %d\tIndividual: %s\tScore: %d\tSteps: %d\n", objective[i].generation, objective[i].individual, objective[i].score, objective[i].steps)
-						best_performer = i
-					}
-				}
+			if best, ok := sim.Best(); ok {
 
-				// Best Individual
+				// Best Individual (less steps)
 				textMessage = text.New(pixel.V(20, 680), atlas)
 				textMessage.Clear()
 				textMessage.Color = colornames.Black
-				fmt.Fprintf(textMessage, "Best Individual: %s\n\nGeneration: %d, with %d steps (Best solution: %d)", objective[best_performer].individual, objective[best_performer].generation, objective[best_performer].steps, map_best_solution)
+				fmt.Fprintf(textMessage, "Best Individual: %s\n\nGeneration: %d, with %d steps (Best solution: %d)", best.Individual, best.Generation, best.Steps, sim.Grid.Best_solution)
 				textMessage.Draw(win, pixel.IM.Scaled(textMessage.Orig, 1))
 
 			} else {
@@ -0,0 +1,375 @@
+package Maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ------------------------ Map Generators ------------------------ //
+
+// Create a map of width x height tiles, with its start and exits
+type Generator interface {
+	Generate(rng *rand.Rand, width int, height int) (*Grid, error)
+}
+
+// Parameters of the built-in generators, options of the map name
+// (map=random:forest:30x20:density=0.4:iterations=3)
+type GeneratorParams struct {
+	Density    float64 // Forest: chance of each tile starting as a tree
+	Birth      int     // Forest: neighbour trees needed to grow a tree on the path
+	Survival   int     // Forest: neighbour trees needed to keep a tree
+	Iterations int     // Forest: number of steps of the cellular automaton
+}
+
+// Default parameters of the map name
+var Default_generator_params = GeneratorParams{
+	Density:    0.4,
+	Birth:      5,
+	Survival:   4,
+	Iterations: 3,
+}
+
+// Built-in map generators, selected by the map name (map=random:prim:30x20:seed=42)
+var map_generators = map[string]func(params GeneratorParams) Generator{
+	"backtracker": func(p GeneratorParams) Generator { return MazeGenerator{Carver: BacktrackerMaze{}} },
+	"prim":        func(p GeneratorParams) Generator { return MazeGenerator{Carver: PrimMaze{}} },
+	"kruskal":     func(p GeneratorParams) Generator { return MazeGenerator{Carver: KruskalMaze{}} },
+	"wilson":      func(p GeneratorParams) Generator { return MazeGenerator{Carver: WilsonMaze{}} },
+	"forest": func(p GeneratorParams) Generator {
+		return ForestGenerator{Density: p.Density, Birth: p.Birth, Survival: p.Survival, Iterations: p.Iterations}
+	},
+}
+
+// Create a built-in map generator by its name
+func NewGenerator(name string, params GeneratorParams) (Generator, error) {
+	generator, ok := map_generators[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("map generator '%s' not found (options: %s)", name, strings.Join(GeneratorNames(), ", "))
+	}
+
+	if params.Density < 0 || params.Density >= 1 {
+		return nil, fmt.Errorf("density should be between 0 and 1")
+	}
+	if params.Birth < 0 || params.Birth > 8 || params.Survival < 0 || params.Survival > 8 {
+		return nil, fmt.Errorf("birth and survival should be between 0 and 8 neighbours")
+	}
+	if params.Iterations < 0 {
+		return nil, fmt.Errorf("number of iterations can't be negative")
+	}
+
+	return generator(params), nil
+}
+
+// Names of the built-in map generators
+func GeneratorNames() []string {
+	var names []string
+	for name := range map_generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate a map by its name: random:<generator>:<width>x<height>[:<option>=<value>...]
+// The width and height are the number of tiles of the map (at least 5). The
+// options are the seed (0 or no seed means random) and the generator parameters.
+// Returns the seed used, that generates the same map again
+func GenerateMap(spec string) (*Grid, int64, error) {
+	var seed int64
+
+	parts := strings.Split(spec, ":")
+	if len(parts) < 3 || parts[0] != "random" {
+		return nil, 0, fmt.Errorf("invalid random map '%s' (format: random:<generator>:<width>x<height>[:seed=<seed>])", spec)
+	}
+
+	width, height, err := parse_size(parts[2])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var generator Generator
+	params := Default_generator_params
+	for _, option := range parts[3:] {
+		var err error
+
+		key, value, _ := strings.Cut(option, "=")
+		switch strings.ToLower(key) {
+		case "seed":
+			seed, err = strconv.ParseInt(value, 10, 64)
+		case "density":
+			params.Density, err = strconv.ParseFloat(value, 64)
+		case "birth":
+			params.Birth, err = strconv.Atoi(value)
+		case "survival":
+			params.Survival, err = strconv.Atoi(value)
+		case "iterations":
+			params.Iterations, err = strconv.Atoi(value)
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid option of random map '%s'", option)
+		}
+	}
+
+	generator, err = NewGenerator(parts[1], params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rng, seed := new_random_source(seed)
+	grid, err := generator.Generate(rng, width, height)
+	return grid, seed, err
+}
+
+// Read a map size like 30x20 (width x height, at least 5x5)
+func parse_size(text string) (int, int, error) {
+	width_text, height_text, _ := strings.Cut(text, "x")
+	width, err_width := strconv.Atoi(width_text)
+	height, err_height := strconv.Atoi(height_text)
+	if err_width != nil || err_height != nil || width < 5 || height < 5 {
+		return 0, 0, fmt.Errorf("invalid map size '%s' (at least 5x5)", text)
+	}
+	return width, height, nil
+}
+
+// ------------------------ Maze Generators ----------------------- //
+
+// Passage between two neighbour cells of a generated maze
+type Passage struct {
+	From Position
+	To   Position
+}
+
+// Create a perfect maze (just one route between any two cells) on a grid of
+// width x height cells, returning the passages that connect the cells
+type Carver interface {
+	Carve(rng *rand.Rand, width int, height int) []Passage
+}
+
+// Neighbour cells inside a grid of width x height cells
+func cell_neighbours(pos Position, width int, height int) []Position {
+	var neighbours []Position
+
+	for direction := up; direction <= right; direction++ {
+		next := pos.move(direction)
+		if next.X >= 0 && next.X < width && next.Y >= 0 && next.Y < height {
+			neighbours = append(neighbours, next)
+		}
+	}
+	return neighbours
+}
+
+// Map of the maze carved by the Carver. The cells of the maze are on the odd
+// lines and columns of the map, so there are walls between them. The start is
+// on the left border and the exit on the right border (like the maps of the
+// maps directory), the walls receive random trees
+type MazeGenerator struct {
+	Carver Carver
+}
+
+func (g MazeGenerator) Generate(rng *rand.Rand, width int, height int) (*Grid, error) {
+
+	// Cells of the maze on the odd lines and columns
+	cells_width, cells_height := (width-1)/2, (height-1)/2
+
+	tiles := make([][]uint8, height)
+	for line := range tiles {
+		tiles[line] = make([]uint8, width)
+		for column := range tiles[line] {
+			tiles[line][column] = uint8(rng.Intn(4) + 1)
+		}
+	}
+
+	// Cell (x, y) is the tile (2x+1, 2y+1), counting the lines from the top
+	for _, passage := range g.Carver.Carve(rng, cells_width, cells_height) {
+		tiles[2*passage.From.Y+1][2*passage.From.X+1] = 0
+		tiles[2*passage.To.Y+1][2*passage.To.X+1] = 0
+		tiles[passage.From.Y+passage.To.Y+1][passage.From.X+passage.To.X+1] = 0
+	}
+
+	// Open the borders, the exit passes through the extra column of even widths
+	start := Position{0, 2*rng.Intn(cells_height) + 1}
+	exit := Position{width - 1, 2*rng.Intn(cells_height) + 1}
+	tiles[start.Y][start.X] = 0
+	for column := 2 * cells_width; column < width; column++ {
+		tiles[exit.Y][column] = 0
+	}
+
+	return new_grid(tiles, start, []Position{exit})
+}
+
+// ---------------------- Recursive Backtracker ------------------- //
+
+// Random walk that goes back (depth-first search) when there isn't a new cell
+// around, creating long corridors
+type BacktrackerMaze struct{}
+
+func (BacktrackerMaze) Carve(rng *rand.Rand, width int, height int) []Passage {
+	var passages []Passage
+
+	visited := make(map[Position]bool, width*height)
+	stack := []Position{{rng.Intn(width), rng.Intn(height)}}
+	visited[stack[0]] = true
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+
+		var options []Position
+		for _, next := range cell_neighbours(current, width, height) {
+			if !visited[next] {
+				options = append(options, next)
+			}
+		}
+
+		// Dead end, go back
+		if len(options) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := options[rng.Intn(len(options))]
+		visited[next] = true
+		passages = append(passages, Passage{current, next})
+		stack = append(stack, next)
+	}
+
+	return passages
+}
+
+// ------------------------ Randomized Prim ----------------------- //
+
+// The maze grows from one cell, connecting a random cell of its border each
+// time, creating many short dead ends
+type PrimMaze struct{}
+
+func (PrimMaze) Carve(rng *rand.Rand, width int, height int) []Passage {
+	var (
+		passages []Passage
+		frontier []Position
+	)
+
+	in_maze := make(map[Position]bool, width*height)
+	in_frontier := make(map[Position]bool, width*height)
+
+	add := func(pos Position) {
+		in_maze[pos] = true
+		for _, next := range cell_neighbours(pos, width, height) {
+			if !in_maze[next] && !in_frontier[next] {
+				in_frontier[next] = true
+				frontier = append(frontier, next)
+			}
+		}
+	}
+	add(Position{rng.Intn(width), rng.Intn(height)})
+
+	for len(frontier) > 0 {
+		// Remove a random cell of the frontier
+		index := rng.Intn(len(frontier))
+		current := frontier[index]
+		frontier[index] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		// Connect it to a random neighbour that is already on the maze
+		var options []Position
+		for _, next := range cell_neighbours(current, width, height) {
+			if in_maze[next] {
+				options = append(options, next)
+			}
+		}
+		passages = append(passages, Passage{options[rng.Intn(len(options))], current})
+		add(current)
+	}
+
+	return passages
+}
+
+// ------------------------ Randomized Kruskal -------------------- //
+
+// All the walls in random order, each one is removed when the cells on its
+// sides aren't connected yet (union-find)
+type KruskalMaze struct{}
+
+func (KruskalMaze) Carve(rng *rand.Rand, width int, height int) []Passage {
+	var walls, passages []Passage
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x+1 < width {
+				walls = append(walls, Passage{Position{x, y}, Position{x + 1, y}})
+			}
+			if y+1 < height {
+				walls = append(walls, Passage{Position{x, y}, Position{x, y + 1}})
+			}
+		}
+	}
+	rng.Shuffle(len(walls), func(i, j int) { walls[i], walls[j] = walls[j], walls[i] })
+
+	// Set of each cell (index y * width + x)
+	parent := make([]int, width*height)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, wall := range walls {
+		set1 := find(wall.From.Y*width + wall.From.X)
+		set2 := find(wall.To.Y*width + wall.To.X)
+		if set1 != set2 {
+			parent[set1] = set2
+			passages = append(passages, wall)
+		}
+	}
+
+	return passages
+}
+
+// ---------------------------- Wilson ---------------------------- //
+
+// Loop-erased random walks from the cells outside the maze until they hit it,
+// so every possible maze has the same chance (uniform spanning tree)
+type WilsonMaze struct{}
+
+func (WilsonMaze) Carve(rng *rand.Rand, width int, height int) []Passage {
+	var passages []Passage
+
+	in_maze := make(map[Position]bool, width*height)
+	in_maze[Position{rng.Intn(width), rng.Intn(height)}] = true
+
+	// Last direction taken from each cell of the walk, overwriting it erases the loops
+	next_cell := make(map[Position]Position, width*height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			start := Position{x, y}
+			if in_maze[start] {
+				continue
+			}
+
+			// Walk until reaching the maze
+			current := start
+			for !in_maze[current] {
+				options := cell_neighbours(current, width, height)
+				next_cell[current] = options[rng.Intn(len(options))]
+				current = next_cell[current]
+			}
+
+			// Add the walk without the loops
+			for current = start; !in_maze[current]; current = next_cell[current] {
+				in_maze[current] = true
+				passages = append(passages, Passage{current, next_cell[current]})
+			}
+		}
+	}
+
+	return passages
+}
@@ -0,0 +1,90 @@
+package Maze
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The generated maps are valid map files (written and read again) with a
+// route from the start to the exit, also the dense forests (their trees that
+// block the routes are removed)
+
+func TestGenerators(t *testing.T) {
+	sizes := [][2]int{{5, 5}, {6, 6}, {7, 5}, {12, 9}, {30, 20}}
+
+	type generator_test struct {
+		name   string
+		params GeneratorParams
+	}
+	var generators []generator_test
+	for _, name := range GeneratorNames() {
+		generators = append(generators, generator_test{name, Default_generator_params})
+	}
+	generators = append(generators,
+		generator_test{"forest", GeneratorParams{Density: 0.9, Birth: 5, Survival: 4, Iterations: 3}},
+		generator_test{"forest", GeneratorParams{Density: 0.6, Birth: 2, Survival: 1, Iterations: 6}},
+		generator_test{"forest", GeneratorParams{Density: 0.5, Birth: 5, Survival: 4, Iterations: 0}},
+	)
+
+	for _, test := range generators {
+		generator, err := NewGenerator(test.name, test.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, size := range sizes {
+			for seed := int64(1); seed <= 5; seed++ {
+				t.Run(fmt.Sprintf("%s %+v %dx%d seed %d", test.name, test.params, size[0], size[1], seed), func(t *testing.T) {
+					check_generated(t, generator, size[0], size[1], seed)
+				})
+			}
+		}
+	}
+}
+
+func check_generated(t *testing.T, generator Generator, width int, height int, seed int64) {
+	grid, err := generator.Generate(rand.New(rand.NewSource(seed)), width, height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if grid.Width() != width || grid.Height() != height {
+		t.Errorf("size %dx%d", grid.Width(), grid.Height())
+	}
+	if grid.Best_solution <= 0 {
+		t.Errorf("best solution %d, the exit should be reachable", grid.Best_solution)
+	}
+
+	var text strings.Builder
+	if err := grid.WriteMap(&text, "generated"); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ParseMap(strings.NewReader(text.String()))
+	if err != nil {
+		t.Fatalf("%s\n%s", err, text.String())
+	}
+	if !reflect.DeepEqual(read.Cells, grid.Cells) || read.Start != grid.Start || !reflect.DeepEqual(read.Exits, grid.Exits) || read.Best_solution != grid.Best_solution {
+		t.Errorf("the map file is a different map:\n%s", text.String())
+	}
+}
+
+// Without a seed the map is random, and the seed returned generates it again
+func TestGenerateMapSeed(t *testing.T) {
+	grid, seed, err := GenerateMap("random:prim:15x11")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seed == 0 {
+		t.Fatal("the seed used wasn't returned")
+	}
+
+	again, again_seed, err := GenerateMap(fmt.Sprintf("random:prim:15x11:seed=%d", seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again_seed != seed || !reflect.DeepEqual(again.Cells, grid.Cells) || again.Start != grid.Start || !reflect.DeepEqual(again.Exits, grid.Exits) {
+		t.Errorf("the seed %d generated a different map", seed)
+	}
+}
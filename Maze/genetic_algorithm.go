@@ -1,46 +1,13 @@
 package Maze
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
-	"os"
-	"regexp"
-	"sort"
-	"strconv"
-	"strings"
 	"time"
 )
 
 var (
-	// --- Main variables --- //
-
-	// Program Variables filled with INI information
-	Population_size    int     // Default value = 100
-	Gene_number        int     // Default value = 50
-	K                  int     // Tournament size (number of participants) // Default value = 25
-	Crossover_rate     float64 // Default value = 0.7
-	Mutation_rate      float64 // I'm analyzing each gene so the mutation rate should be really small // Default value = 0.05
-	Generations        int     // Default value = 100
-	Elitism_percentual int     // Default value = 10 (10% of population size)
-
-	// Other variables
-	population          []string
-	population_score    []int
-	elitism_individuals int = (Elitism_percentual * Population_size) / 100
-
-	// Counters
-	mutation_count, mutation_ind_count int
-	current_generation                 int = 0
-	best_step                          int = 0
-
-	// Print into screen variables
-	print_best                    = ""
-	print_score                   = 0
-	print_current_generation      = 0
-	print_crossover_count         = 0
-	print_max_generation_position = 0
-	print_average_score           = 0
-
 	// Debug
 	debug bool = false
 )
@@ -66,103 +33,77 @@ func slice_average(slice []int, total int) (int, int) {
 }
 
 // ------------------- Validate Parameters -------------------- //
-func validate_parameters(pop_size int, competitors int) {
+func validate_parameters(pop_size int, competitors int, gene_nr int, elitism_percentual int) error {
 	// Minimal Population Size size accepted is 2
 	if pop_size%2 == 1 {
-		fmt.Printf("\nPopulation size should be ODD numbers. Exiting\n")
-		os.Exit(0)
+		return errors.New("population size should be EVEN numbers")
 	}
 
 	// Population Size should be positive
 	if pop_size <= 0 {
-		fmt.Printf("\nPopulation size should be Positive. Exiting\n")
-		os.Exit(0)
+		return errors.New("population size should be positive")
+	}
+
+	// Each command needs 2 genes
+	if gene_nr < 2 {
+		return errors.New("gene number must be at least 2")
 	}
 
 	// K (competitors) must be at least 2
 	if competitors < 2 {
-		fmt.Printf("\nNumber of competitors (k) must be at least 2. Exiting\n")
-		os.Exit(0)
+		return errors.New("number of competitors (k) must be at least 2")
 	}
-}
 
-// ------------------- Generate Individuals ------------------- //
-func generate_individuals(gene_nr int) string {
-	var individual string = ""
-
-	// Initialize rand source
-	rand.Seed(time.Now().UnixNano())
-
-	for i := 0; i < gene_nr; i++ {
-		individual += strconv.Itoa(rand.Intn(2))
+	// The elite can't be bigger than the population
+	if elitism_percentual < 0 || elitism_percentual > 100 {
+		return errors.New("elitism percentual should be between 0 and 100")
 	}
 
-	return individual
+	return nil
 }
 
-// --------- Generate the Evaluation of an Individual --------- //
-func fitness_individual(individual string) int {
-	ones := regexp.MustCompile("1")
-	matches := ones.FindAllStringIndex(individual, -1)
+// ------------------- Random Number Source ------------------- //
+func new_random_source(seed int64) (*rand.Rand, int64) {
+	// Seed 0 means random, so choose one that can be replayed later
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
 
-	return len(matches)
+	return rand.New(rand.NewSource(seed)), seed
 }
 
-// --------- Generate the Evaluation of a Population ---------- //
-func fitness_population(pop []string) []int {
-
-	var score []int
-
-	for i := 0; i < len(pop); i++ {
-		score = append(score, fitness_individual(pop[i]))
+// ------------------- Generate Individuals ------------------- //
+// Fill the individual with random commands
+func generate_individuals(rng *rand.Rand, individual Genome) {
+	for i := range individual {
+		individual[i] = Direction(rng.Intn(4))
 	}
-
-	return score
 }
 
 // ------------------------- Elitism -------------------------- //
-func elitism(pop []string, pop_score []int, pop_size int, elitism_number int) ([]string, []string) {
-	var (
-		elite, elite_score, tmp_slice []string
-	)
-
-	// Append score + individual in one slice
-	for i := 0; i < pop_size; i++ {
-		tmp_slice = append(tmp_slice, strconv.Itoa(pop_score[i])+","+pop[i])
-	}
-
-	// Sort slice
-	sort.Strings(tmp_slice)
-
-	// Insert individuals on Elite slice and score on elite_score
-	for i := pop_size - 1; i > (pop_size-1)-elitism_number; i-- {
-		tmp_slice := strings.Split(tmp_slice[i], ",")
-		elite = append(elite, tmp_slice[1])             // Individual
-		elite_score = append(elite_score, tmp_slice[0]) //Score
-	}
-
-	return elite, elite_score
+// Indexes of the elitism_number best individuals (ties keep the population order)
+func elitism(pop_score []int, elitism_number int) []int {
+	return ranked_indexes(pop_score)[:elitism_number]
 }
 
 // ---------------------- Define Parents ---------------------- //
-func define_parents(pop []string, pop_size int, k int) []string {
-	var parents []string
+// The competitors are ranked by the score they got on the maze (pop_score)
+// Returns the indexes of the winners
+func define_parents(rng *rand.Rand, pop Population, pop_score []int, pop_size int, k int) []int {
+	var parents []int
 
 	// Quantity of tournaments is equal to the size of population
 	for tournament := 0; tournament < pop_size; tournament++ {
 		var (
-			competitors []string
+			competitors []int
 			score       []int
 		)
 
 		// Each tournament, K competitors
 		for i := 0; i < k; i++ {
-			competitors = append(competitors, pop[rand.Intn(pop_size)])
-		}
-
-		// Calculate the score of K competitors
-		for i := 0; i < k; i++ {
-			score = append(score, fitness_individual(competitors[i]))
+			index := rng.Intn(pop_size)
+			competitors = append(competitors, index)
+			score = append(score, pop_score[index])
 		}
 
 		bigger := score[0]
@@ -178,7 +119,7 @@ func define_parents(pop []string, pop_size int, k int) []string {
 		parents = append(parents, winner)
 
 		if debug {
-			fmt.Printf("\tTournament: %d\t Competitors: %s\t Scores: %d\t Winner: %s (%d)\n", tournament, competitors, score, winner, bigger)
+			fmt.Printf("\tTournament: %d\t Competitors: %d\t Scores: %d\t Winner: %s (%d)\n", tournament, competitors, score, pop[winner], bigger)
 		}
 
 	}
@@ -188,160 +129,126 @@ func define_parents(pop []string, pop_size int, k int) []string {
 }
 
 // -------------------- Generate Children --------------------- //
-func generate_children(parents []string, pop_size int, elitism_number int, elite []string) ([]string, int) {
+// parents and elite are indexes of the population, traces are the runs of the population on the maze
+// The children are written on pop_new, that must have the same size of pop
+func generate_children(rng *rand.Rand, crossover Crossover, pop Population, pop_new Population, traces []Trace, parents []int, crossover_rate float64, elite []int) int {
 	var (
-		father1, father2, child1, child2 string
-		pop_new                          []string
-		cross_count                      int = 0
+		father1, father2 Genome
+		cross_count      int = 0
 	)
 
 	if debug {
 		fmt.Printf("\n\tSelected parents:\n")
 	}
 
-	for i := 0; i < pop_size/2; i++ {
+	for i := 0; i < len(pop)/2; i++ {
+		child1, child2 := pop_new[i*2], pop_new[i*2+1]
+
 		// Define the couples
-		randomIndex := rand.Intn(len(parents))
-		father1 = parents[randomIndex]
+		index1 := parents[rng.Intn(len(parents))]
+		father1 = pop[index1]
 
-		randomIndex = rand.Intn(len(parents))
-		father2 = parents[randomIndex]
+		index2 := parents[rng.Intn(len(parents))]
+		father2 = pop[index2]
 
 		if debug {
 			fmt.Printf("\t%d) %s with %s\n", i, father1, father2)
 		}
 
 		// Define if will have crossover (the parents will be copied to next generation)
-		if rand.Float64() < Crossover_rate {
-
-			// Define the cut-point
-			cut_point := rand.Intn(Gene_number-1) + 1
-			if debug {
-				fmt.Printf("\t\tCut-point: %d\n", cut_point)
-			}
-
-			// Split father's values
-			// Father1
-			father1_split := strings.Split(father1, "")
-			father1_split_p1 := father1_split[0:cut_point]
-			father1_split_p2 := father1_split[cut_point:]
-			// Father2
-			father2_split := strings.Split(father2, "")
-			father2_split_p1 := father2_split[0:cut_point]
-			father2_split_p2 := father2_split[cut_point:]
-
-			// Child1
-			child1_p1 := strings.Join(father1_split_p1, "")
-			child1_p2 := strings.Join(father2_split_p2, "")
-			child1 = child1_p1 + child1_p2
-			if debug {
-				fmt.Printf("\t\tChild1: %s + %s: %s\n", child1_p1, child1_p2, child1)
-			}
+		if rng.Float64() < crossover_rate {
 
-			// Child2
-			child2_p1 := strings.Join(father2_split_p1, "")
-			child2_p2 := strings.Join(father1_split_p2, "")
-			child2 = child2_p1 + child2_p2
+			crossover.Cross(rng, father1, father2, child1, child2, &traces[index1], &traces[index2])
 			if debug {
-				fmt.Printf("\t\tChild2: %s + %s: %s\n", child2_p1, child2_p2, child2)
+				fmt.Printf("\t\tChild1: %s\n", child1)
+				fmt.Printf("\t\tChild2: %s\n", child2)
 			}
-
-			// Put the childs in the new generation
-			pop_new = append(pop_new, child1)
-			pop_new = append(pop_new, child2)
+			cross_count++
 
 		} else {
+			copy(child1, father1)
+			copy(child2, father2)
 			if debug {
-				fmt.Printf("\t\tCrossover:\n")
-			}
-			pop_new = append(pop_new, father1)
-			pop_new = append(pop_new, father2)
-			if debug {
+				fmt.Printf("\t\tNo crossover:\n")
 				fmt.Printf("\t\tChild1 (Father1): %s\n", father1)
 				fmt.Printf("\t\tChild2 (Father2): %s\n", father2)
 			}
-			cross_count++
 		}
 
 	}
 
 	// Ensure place of elite members on next generation
-	if elitism_number > 0 {
+	if len(elite) > 0 {
 		if debug {
 			fmt.Printf("\n\tElitism: Regular individual removal:\n")
 		}
 
-		// Remove randomically the number os elite elements
-		for i := 0; i < elitism_number; i++ {
-			random := rand.Intn(len(pop_new))
+		// Remove randomically the number os elite elements, moving them to the end of pop_new
+		size := len(pop_new)
+		for i := 0; i < len(elite); i++ {
+			random := rng.Intn(size)
 			if debug {
 				fmt.Printf("\t\tIndividual %d:\t%s removed randomically from new population\n", i, pop_new[random])
 			}
 
-			// Remove the element at index 'random' from pop_new
-			pop_new[random] = pop_new[len(pop_new)-1] // Copy last element to index 'random'.
-			pop_new[len(pop_new)-1] = ""              // Erase last element (write zero value).
-			pop_new = pop_new[:len(pop_new)-1]        // Truncate slice.
+			pop_new[random], pop_new[size-1] = pop_new[size-1], pop_new[random]
+			size--
 		}
 
-		// Insert Elite Members on next generation
+		// Insert Elite Members on next generation (on the removed places)
 		if debug {
 			fmt.Printf("\n\tElitism: Elite individual insertion:\n")
 		}
-		for i := 0; i < elitism_number; i++ {
-			pop_new = append(pop_new, elite[i])
+		for i := 0; i < len(elite); i++ {
+			copy(pop_new[size+i], pop[elite[i]])
 			if debug {
-				fmt.Printf("\t\tIndividual %d\t%s inserted to new population\n", i, elite[i])
+				fmt.Printf("\t\tIndividual %d\t%s inserted to new population\n", i, pop[elite[i]])
 			}
 		}
 	}
 
-	return pop_new, cross_count
+	return cross_count
 }
 
 // ------------------------- Mutation ------------------------- //
-func generate_mutation(new_pop []string, pop_size int, gene_nr int, Mutation_rate float64) ([]string, int, int) {
+// Each gene is checked with the Mutation_rate and an operator of the mix is chosen.
+// The flip changes the gene, the command operators change the command just when
+// the gene is the first of the command, so they are rolled once per command
+// The population is changed in place
+func generate_mutation(rng *rand.Rand, mix MutationMix, pop Population, Mutation_rate float64) (int, int, int) {
 
 	var (
-		new_pop_mutated   []string
 		count_genes       int = 0
+		count_commands    int = 0
 		count_individuals int = 0
 	)
 
 	// For all individuals in population
-	for i := 0; i < pop_size; i++ {
-
-		var (
-			individual              string = ""
-			individual_mutated_flag bool
-		)
+	for i := 0; i < len(pop); i++ {
 
-		individual = new_pop[i]
+		individual := pop[i]
+		individual_mutated_flag := false
 
 		// For each gene, check for mutations
-		for gene := 0; gene < gene_nr; gene++ {
+		for gene := 0; gene < individual.genes(); gene++ {
 
 			// Check if there is a mutation
-			if Mutation_rate >= rand.Float64() {
-
-				individual_split := strings.Split(individual, "")
-
-				// Invert the mutated gene
-				if individual_split[gene] == "0" {
-					individual_split[gene] = "1"
+			if Mutation_rate >= rng.Float64() {
 
+				operator := mix.choose(rng)
+				if gene_operator(operator) {
+					count_genes++ // Generation genes mutated count
+				} else if gene%2 == 0 {
+					count_commands++ // Generation commands mutated count
 				} else {
-					individual_split[gene] = "0"
+					continue
 				}
-
-				// Update the mutated individual
-				individual = strings.Join(individual_split, "")
+				operator.Mutate(rng, individual, gene)
 
 				if debug {
-					fmt.Printf("\tIndividual #%d (%s) mutated on gene %d. New Individual: %s \n", i, new_pop[i], gene, individual)
+					fmt.Printf("\tIndividual #%d mutated on gene %d. New Individual: %s \n", i, gene, individual)
 				}
 
-				count_genes++ // Generation genes mutated count
 				individual_mutated_flag = true
 
 			}
@@ -351,30 +258,22 @@ func generate_mutation(new_pop []string, pop_size int, gene_nr int, Mutation_rat
 		// Generation individuals mutated count
 		if individual_mutated_flag {
 			count_individuals++
-			individual_mutated_flag = false
 		}
-
-		// Add mutated individuals to a new generation
-		new_pop_mutated = append(new_pop_mutated, individual)
 	}
 
-	return new_pop_mutated, count_genes, count_individuals
+	return count_genes, count_commands, count_individuals
 }
 
 // --------------------- Best Individual ---------------------- //
-func best_individual() (string, int) {
-	// var score []int
-	//
-	// // Calculate the score of the latest population
-	// score = fitness_population(population)
-
-	bigger := population_score[0]
-	winner := population[0]
-
-	for i := 0; i < len(population_score); i++ {
-		if population_score[i] > bigger {
-			bigger = population_score[i]
-			winner = population[i]
+// Index and score of the best individual
+func best_individual(pop_score []int) (int, int) {
+	bigger := pop_score[0]
+	winner := 0
+
+	for i := 0; i < len(pop_score); i++ {
+		if pop_score[i] > bigger {
+			bigger = pop_score[i]
+			winner = i
 		}
 	}
 
@@ -382,36 +281,24 @@ func best_individual() (string, int) {
 }
 
 // ------------------------- MAIN FUNCTION ------------------------- //
-func genetic_algorithm() {
-
-	// // --------------------- Validate parameters --------------------- //
-	// validate_parameters(Population_size, k)
-	//
-	//
-	// // ----------------- 0 - Generate the population ----------------- //
-	// // Generate each individual for population
-	// for i := 0 ; i < Population_size ; i++ {
-	//   population = append( population, generate_individuals(Gene_number) )
-	// }
-
-	// ----------------------- Generations Loop ---------------------- //
-	// for i := 0 ; i < Generations ; i ++ {
+func (sim *Simulation) genetic_algorithm() {
+	cfg := sim.Config
 
 	if debug {
-		fmt.Printf("\n// ---------------------------------- GENERATION: %d ---------------------------------- //\n\n", current_generation)
+		fmt.Printf("\n// ---------------------------------- GENERATION: %d ---------------------------------- //\n\n", sim.current_generation)
 	}
 
 	// ----------------------- 1 - Evaluation ------------------------ //
 	if debug {
 		fmt.Printf("1 - Evaluation:\n\n")
 	}
-	// population_score = fitness_population(population)
-	// Evaluation
+	// The population was evaluated on the maze (population_score), the same
+	// score is used by the parents selection, elitism and best individual
 
 	// Show the evaluation of each individual
 	if debug {
-		for i := 0; i < Population_size; i++ {
-			fmt.Printf("\tIndividual %d:\t%s\tEvaluation %d\n", i, population[i], population_score[i])
+		for i := 0; i < cfg.Population_size; i++ {
+			fmt.Printf("\tIndividual %d:\t%s\tEvaluation %d\n", i, sim.Population[i], sim.population_score[i])
 		}
 	}
 
@@ -420,72 +307,102 @@ func genetic_algorithm() {
 		fmt.Printf("\n2 - Define Parents:\n\n")
 	}
 
-	parents := define_parents(population, Population_size, K)
+	parents := cfg.Selection.Select(sim.rng, sim.Population, sim.population_score, sim.current_generation)
 
 	if debug {
-		fmt.Printf("\n\tParents: %s\n\n", parents)
+		fmt.Printf("\n\tParents (indexes): %d\n\n", parents)
 	}
 
 	// ------------------------- 3 - Elitism ------------------------- //
-	elite, elite_score := elitism(population, population_score, Population_size, elitism_individuals)
+	elite := elitism(sim.population_score, sim.elitism_individuals)
 	if debug {
-		fmt.Printf("\n3 - Elitism:\n\n\tNumber of elite members: %d\n\n", elitism_individuals)
-		for i := 0; i < elitism_individuals; i++ {
-			fmt.Printf("\tIndividual %d:\t%s set for elite with score: %s\n", i, elite[i], elite_score[i])
+		fmt.Printf("\n3 - Elitism:\n\n\tNumber of elite members: %d\n\n", sim.elitism_individuals)
+		for i := 0; i < sim.elitism_individuals; i++ {
+			fmt.Printf("\tIndividual %d:\t%s set for elite with score: %d\n", i, sim.Population[elite[i]], sim.population_score[elite[i]])
 		}
 	}
 
 	// -------------------- 4 - Generate Children -------------------- //
-	new_population, crossover_count := generate_children(parents, Population_size, elitism_individuals, elite)
+	// The children are written on the buffer of the next population (no allocations)
+	new_population := sim.next_population
+	crossover_count := generate_children(sim.rng, cfg.Crossover, sim.Population, new_population, sim.population_traces, parents, cfg.Crossover_rate, elite)
 	if debug {
 		fmt.Printf("\n4 - Generate Chindren:\n\n\tNew population: %s\n", new_population)
 	}
 
 	// ------------------------ 5 - Mutation ------------------------- //
-	new_population, mutation_count, mutation_ind_count = generate_mutation(new_population, Population_size, Gene_number, Mutation_rate)
+	mutation_count, mutation_command_count, mutation_ind_count := generate_mutation(sim.rng, cfg.Mutation_mix, new_population, sim.mutation_rate)
 	if debug {
 		fmt.Printf("\n5 - Mutation:\n\tMutated Generation: %s\n\n", new_population)
 	}
 
+	// Best individual of the evaluated population (before replacing it)
+	best_index, score := best_individual(sim.population_score)
+	best := sim.Population[best_index].Clone()
+
 	// ---- 6 - Replace population vector with new population one ---- //
-	population = nil // Clean ond population
-	for i := 0; i < len(new_population); i++ {
-		population = append(population, new_population[i])
-	}
+	// The old population is the buffer of the next generation
+	sim.Population, sim.next_population = new_population, sim.Population
 
 	average_score := 0
-	for i := 0; i < len(population_score); i++ {
-		average_score += population_score[i]
+	for i := 0; i < len(sim.population_score); i++ {
+		average_score += sim.population_score[i]
 	}
 
-	average_score = average_score / len(population_score)
+	average_score = average_score / len(sim.population_score)
+
+	deaths := 0
+	for i := range sim.population_traces {
+		if sim.population_traces[i].Death_step > 0 {
+			deaths++
+		}
+	}
 
 	// -------------------- 7 - Best individual ---------------------- //
 
 	// Print debug to console
-	best, score := best_individual()
-	fmt.Printf("\nGENERATION: %d\n", current_generation)
-	fmt.Printf("Mutated individuals: %d\t\tMutated Genes: %d\n", mutation_ind_count, mutation_count)
-	fmt.Printf("Crossovers: %d\n", crossover_count)
-	fmt.Printf("Best Individual: %s\n", best)
-	fmt.Printf("Fitness Average: %d\n\n", average_score)
-	fmt.Printf("Maximum position: %d\tFitness: %d\n\n", max_generation_position+1, score)
-
-	// Keep the max number of steps reached
-	if max_generation_position+1 > best_step {
-		best_step = max_generation_position + 1
+	fmt.Fprintf(cfg.Output, "\nGENERATION: %d\t\tSeed: %d\n", sim.current_generation, cfg.Seed)
+	fmt.Fprintf(cfg.Output, "Mutated individuals: %d\t\tMutated Genes: %d\tMutated Commands: %d\tRate: %.3f (%s)\n", mutation_ind_count, mutation_count, mutation_command_count, sim.mutation_rate, cfg.Mutation_mix)
+	fmt.Fprintf(cfg.Output, "Crossovers: %d\n", crossover_count)
+	fmt.Fprintf(cfg.Output, "Best Individual: %s\n", best)
+	fmt.Fprintf(cfg.Output, "Fitness Average: %d\n\n", average_score)
+	fmt.Fprintf(cfg.Output, "Closest distance to exit: %d\tFitness: %d\n\n", sim.closest_distance, score)
+	if sim.Grid.has_hazards() || len(sim.Grid.Enemies) > 0 {
+		fmt.Fprintf(cfg.Output, "Deaths: %d\n\n", deaths)
 	}
 
-	// Now set the variables to be printed on screen
-	print_best, print_score = best_individual()
-	print_current_generation = current_generation
-	print_crossover_count = crossover_count
-	print_max_generation_position = max_generation_position
-	print_average_score = average_score
+	// Keep the closest distance reached
+	if sim.best_distance < 0 || sim.closest_distance < sim.best_distance {
+		sim.best_distance = sim.closest_distance
+	}
 
-	// Restart Variables
-	population_score = nil
+	// Now set the variables to be printed on screen
+	sim.stats = Stats{
+		Generation:          sim.current_generation,
+		Mutated_individuals: mutation_ind_count,
+		Mutated_genes:       mutation_count,
+		Mutated_commands:    mutation_command_count,
+		Mutation_rate:       sim.mutation_rate,
+		Mutation_mix:        cfg.Mutation_mix.String(),
+		Crossovers:          crossover_count,
+		Best:                best,
+		Best_score:          score,
+		Average_score:       average_score,
+		Closest_distance:    sim.closest_distance,
+		Deaths:              deaths,
+	}
 
-	// }
+	// ------------------ 8 - Adaptive mutation rate ----------------- //
+	improved := sim.current_generation == 0 || score > sim.best_score
+	if improved {
+		sim.best_score = score
+		sim.stagnant_generations = 0
+	} else {
+		sim.stagnant_generations++
+	}
+	sim.mutation_rate = cfg.Adaptive_mutation.next_rate(sim.mutation_rate, cfg.Mutation_rate, improved, sim.stagnant_generations)
 
+	// Restart Variables
+	sim.population_score = nil
+	sim.population_traces = nil
 }
@@ -0,0 +1,49 @@
+package Maze
+
+import "strings"
+
+// --------------------------- Genome ----------------------------- //
+
+// Individual of the population, one command (Direction) for each pair of genes
+// The genes are the bits of the commands: up = 00, down = 01, left = 10, right = 11
+type Genome []Direction
+
+// Individuals of one generation
+type Population []Genome
+
+// Binary representation of the genes (the same of the INI Gene_number)
+func (individual Genome) String() string {
+	var text strings.Builder
+
+	text.Grow(len(individual) * 2)
+	for _, command := range individual {
+		text.WriteByte(byte('0' + command>>1))
+		text.WriteByte(byte('0' + command&1))
+	}
+	return text.String()
+}
+
+// Copy of the individual that isn't changed when the population buffers are reused
+func (individual Genome) Clone() Genome {
+	return append(Genome(nil), individual...)
+}
+
+// Number of genes (bits) of the individual
+func (individual Genome) genes() int {
+	return len(individual) * 2
+}
+
+// Mask of the gene inside its command (the first gene is the high bit)
+func gene_mask(gene int) Direction {
+	return 2 >> uint(gene%2)
+}
+
+// Create a population with all individuals allocated on a single block
+func new_population(pop_size int, commands int) Population {
+	pop := make(Population, pop_size)
+	block := make([]Direction, pop_size*commands)
+	for i := range pop {
+		pop[i] = block[i*commands : (i+1)*commands : (i+1)*commands]
+	}
+	return pop
+}
@@ -0,0 +1,247 @@
+package Maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Benchmarks of the packed genome against the original binary strings
+// go test -bench . -benchmem ./Maze
+
+const (
+	benchmark_population = 5000
+	benchmark_genes      = 400
+)
+
+// ------------------- Original string genome ------------------- //
+
+func legacy_generate_individuals(rng *rand.Rand, gene_nr int) string {
+	var individual string = ""
+
+	for i := 0; i < gene_nr; i++ {
+		individual += strconv.Itoa(rng.Intn(2))
+	}
+
+	return individual
+}
+
+func legacy_elitism(pop []string, pop_score []int, pop_size int, elitism_number int) ([]string, []string) {
+	var elite, elite_score, tmp_slice []string
+
+	for i := 0; i < pop_size; i++ {
+		tmp_slice = append(tmp_slice, strconv.Itoa(pop_score[i])+","+pop[i])
+	}
+
+	sort.Strings(tmp_slice)
+
+	for i := pop_size - 1; i > (pop_size-1)-elitism_number; i-- {
+		tmp_slice := strings.Split(tmp_slice[i], ",")
+		elite = append(elite, tmp_slice[1])
+		elite_score = append(elite_score, tmp_slice[0])
+	}
+
+	return elite, elite_score
+}
+
+func legacy_generate_children(rng *rand.Rand, parents []string, pop_size int, gene_nr int, crossover_rate float64) []string {
+	var pop_new []string
+
+	for i := 0; i < pop_size/2; i++ {
+		father1 := parents[rng.Intn(len(parents))]
+		father2 := parents[rng.Intn(len(parents))]
+
+		if rng.Float64() < crossover_rate {
+			cut_point := rng.Intn(gene_nr-1) + 1
+
+			father1_split := strings.Split(father1, "")
+			father2_split := strings.Split(father2, "")
+
+			child1 := strings.Join(father1_split[0:cut_point], "") + strings.Join(father2_split[cut_point:], "")
+			child2 := strings.Join(father2_split[0:cut_point], "") + strings.Join(father1_split[cut_point:], "")
+
+			pop_new = append(pop_new, child1, child2)
+		} else {
+			pop_new = append(pop_new, father1, father2)
+		}
+	}
+
+	return pop_new
+}
+
+func legacy_generate_mutation(rng *rand.Rand, new_pop []string, pop_size int, gene_nr int, mutation_rate float64) []string {
+	var new_pop_mutated []string
+
+	for i := 0; i < pop_size; i++ {
+		individual := new_pop[i]
+
+		for gene := 0; gene < gene_nr; gene++ {
+			if mutation_rate >= rng.Float64() {
+				individual_split := strings.Split(individual, "")
+
+				if individual_split[gene] == "0" {
+					individual_split[gene] = "1"
+				} else {
+					individual_split[gene] = "0"
+				}
+
+				individual = strings.Join(individual_split, "")
+			}
+		}
+
+		new_pop_mutated = append(new_pop_mutated, individual)
+	}
+
+	return new_pop_mutated
+}
+
+func legacy_individual_to_commands(pop []string, gene_nr int) [][]Direction {
+	commands := make([][]Direction, len(pop))
+	for i := 0; i < len(pop); i++ {
+		commands[i] = make([]Direction, gene_nr/2)
+	}
+
+	for i := 0; i < len(pop); i++ {
+		individual_split := strings.Split(pop[i], "")
+
+		index := 0
+		for j := 0; j < len(individual_split)/2; j++ {
+			code := fmt.Sprintf("%s%s", individual_split[index], individual_split[index+1])
+
+			if code == "00" {
+				commands[i][j] = 0
+			} else if code == "01" {
+				commands[i][j] = 1
+			} else if code == "10" {
+				commands[i][j] = 2
+			} else if code == "11" {
+				commands[i][j] = 3
+			}
+
+			index += 2
+		}
+	}
+	return commands
+}
+
+// --------------------------- Helpers -------------------------- //
+
+func benchmark_legacy_population(rng *rand.Rand) ([]string, []int) {
+	var (
+		pop   []string
+		score []int
+	)
+
+	for i := 0; i < benchmark_population; i++ {
+		pop = append(pop, legacy_generate_individuals(rng, benchmark_genes))
+		score = append(score, rng.Intn(10000))
+	}
+	return pop, score
+}
+
+func benchmark_packed_population(rng *rand.Rand) (Population, Population, []int, []int) {
+	pop := new_population(benchmark_population, benchmark_genes/2)
+	pop_new := new_population(benchmark_population, benchmark_genes/2)
+
+	var (
+		score   []int
+		parents []int
+	)
+	for i := 0; i < benchmark_population; i++ {
+		generate_individuals(rng, pop[i])
+		score = append(score, rng.Intn(10000))
+		parents = append(parents, i)
+	}
+	return pop, pop_new, score, parents
+}
+
+// -------------------------- Crossover ------------------------- //
+
+func BenchmarkCrossoverLegacy(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, _ := benchmark_legacy_population(rng)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy_generate_children(rng, pop, benchmark_population, benchmark_genes, 0.7)
+	}
+}
+
+func BenchmarkCrossoverPacked(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, pop_new, _, parents := benchmark_packed_population(rng)
+	traces := make([]Trace, benchmark_population)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generate_children(rng, PointCrossover{Points: 1}, pop, pop_new, traces, parents, 0.7, nil)
+	}
+}
+
+// -------------------------- Mutation -------------------------- //
+
+func BenchmarkMutationLegacy(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, _ := benchmark_legacy_population(rng)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy_generate_mutation(rng, pop, benchmark_population, benchmark_genes, 0.05)
+	}
+}
+
+func BenchmarkMutationPacked(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, _, _, _ := benchmark_packed_population(rng)
+	mix := MutationMix{{Name: "flip", Operator: FlipMutation{}, Weight: 1}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		generate_mutation(rng, mix, pop, 0.05)
+	}
+}
+
+// -------------------------- Elitism --------------------------- //
+
+func BenchmarkElitismLegacy(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, score := benchmark_legacy_population(rng)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy_elitism(pop, score, benchmark_population, benchmark_population/10)
+	}
+}
+
+func BenchmarkElitismPacked(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	_, _, score, _ := benchmark_packed_population(rng)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		elitism(score, benchmark_population/10)
+	}
+}
+
+// -------------------------- Decoding -------------------------- //
+
+// The packed genome is already a slice of commands, so there is nothing to decode
+func BenchmarkDecodeLegacy(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	pop, _ := benchmark_legacy_population(rng)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		legacy_individual_to_commands(pop, benchmark_genes)
+	}
+}
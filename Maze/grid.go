@@ -0,0 +1,185 @@
+package Maze
+
+import (
+	"sync"
+)
+
+// ----------------------------- Grid ----------------------------- //
+
+// Map where the players walk
+// Cells[line][column]: line 0 is the top of the screen, while the players
+// coordinates count the Y axis from the bottom
+type Grid struct {
+	Cells         [][]uint8
+	Start         Position   // Start cell of the map file (S)
+	Exits         []Position // Exit cells of the map file (E)
+	Best_solution int        // Number of steps of the shortest route from the start to an exit
+	Enemies       []Patrol   // Enemies of the map file (@enemy), ignored by the shortest route
+	Map_seed      int64      // Seed of the random maps (random:...:seed=<seed> generates it again), 0 = map file
+
+	// Distance of each position (and keys held) to the nearest exit, calculated on the first use
+	distances      [][][]int
+	distances_once sync.Once
+
+	// Partner of each teleporter, found on the first use
+	partners      map[Position]Position
+	partners_once sync.Once
+}
+
+// Keys and doors, tiles of the map files a - d (keys) and A - D (doors)
+const (
+	key_tile   = 10 // Keys 10 - 13, picked up when the player enters the tile
+	door_tile  = 20 // Doors 20 - 23, open for the players that have the key of the same color
+	key_colors = 4
+)
+
+// Hazards, tiles of the map files ~ (water), % (lava) and ^ (spikes). The
+// players can enter them, but they die there
+const (
+	hazard_tile  = 30 // Hazards 30 - 32: water, lava and spikes
+	hazard_kinds = 3
+)
+
+func is_hazard(tile uint8) bool {
+	return tile >= hazard_tile && tile < hazard_tile+hazard_kinds
+}
+
+// Teleporters, tiles of the map files w - z, and one-way tiles n (up), v
+// (down), < (left) and > (right)
+const (
+	teleporter_tile  = 40 // Teleporters 40 - 43, each one moves the player to the other tile of the same letter
+	teleporter_pairs = 4
+	one_way_tile     = 50 // One-way tiles 50 - 53 (one_way_tile + Direction), entered and left just on their direction
+)
+
+// Pair of a teleporter tile
+func teleporter_pair(tile uint8) (int, bool) {
+	return int(tile) - teleporter_tile, tile >= teleporter_tile && tile < teleporter_tile+teleporter_pairs
+}
+
+// Direction of a one-way tile
+func one_way_direction(tile uint8) (Direction, bool) {
+	return Direction(tile - one_way_tile), tile >= one_way_tile && tile <= one_way_tile+uint8(right)
+}
+
+// Keys held by a player, one bit for each color (bit 0 opens the door A)
+type Keys uint8
+
+// Color of a key tile
+func key_color(tile uint8) (int, bool) {
+	return int(tile) - key_tile, tile >= key_tile && tile < key_tile+key_colors
+}
+
+// Color of a door tile
+func door_color(tile uint8) (int, bool) {
+	return int(tile) - door_tile, tile >= door_tile && tile < door_tile+key_colors
+}
+
+// Check if the key of the color is held
+func (keys Keys) Has(color int) bool {
+	return keys&(1<<color) != 0
+}
+
+// Keys held after entering the tile
+func (keys Keys) pick(tile uint8) Keys {
+	if color, ok := key_color(tile); ok {
+		return keys | 1<<color
+	}
+	return keys
+}
+
+// Letters of the keys held (like the map files)
+func (keys Keys) String() string {
+	var letters []byte
+	for color := 0; color < key_colors; color++ {
+		if keys.Has(color) {
+			letters = append(letters, byte('a'+color))
+		}
+	}
+	return string(letters)
+}
+
+// Check if the tile can be entered with the keys: the path, the keys, the
+// doors of the keys held, the hazards, the teleporters and the one-way tiles
+// (from their direction)
+func passable(tile uint8, keys Keys) bool {
+	if color, ok := door_color(tile); ok {
+		return keys.Has(color)
+	}
+	_, key := key_color(tile)
+	_, teleporter := teleporter_pair(tile)
+	_, one_way := one_way_direction(tile)
+	return tile == 0 || key || is_hazard(tile) || teleporter || one_way
+}
+
+// Number of columns
+func (grid *Grid) Width() int {
+	return len(grid.Cells[0])
+}
+
+// Number of lines
+func (grid *Grid) Height() int {
+	return len(grid.Cells)
+}
+
+// Tile on the player coordinates (Y from the bottom)
+func (grid *Grid) Tile(x int, y int) uint8 {
+	return grid.Cells[len(grid.Cells)-1-y][x]
+}
+
+// Check if the position is inside the grid and there isn't an object on it
+// (the doors are open with the keys held)
+func (grid *Grid) Walkable(x int, y int, keys Keys) bool {
+	if x < 0 || x >= grid.Width() || y < 0 || y >= grid.Height() {
+		return false
+	}
+	return passable(grid.Tile(x, y), keys)
+}
+
+// Check if the map has any hazard
+func (grid *Grid) has_hazards() bool {
+	for _, line := range grid.Cells {
+		for _, tile := range line {
+			if is_hazard(tile) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Partner of each teleporter tile (the teleporters without a partner aren't
+// on it, they are refused when the map is loaded)
+func (grid *Grid) teleporters() map[Position]Position {
+	grid.partners_once.Do(func() {
+		tiles := make(map[int][]Position)
+		for y := 0; y < grid.Height(); y++ {
+			for x := 0; x < grid.Width(); x++ {
+				if pair, ok := teleporter_pair(grid.Tile(x, y)); ok {
+					tiles[pair] = append(tiles[pair], Position{x, y})
+				}
+			}
+		}
+
+		grid.partners = make(map[Position]Position)
+		for _, pair := range tiles {
+			if len(pair) == 2 {
+				grid.partners[pair[0]], grid.partners[pair[1]] = pair[1], pair[0]
+			}
+		}
+	})
+	return grid.partners
+}
+
+// Number of combinations of the keys of the map (1 without keys)
+func (grid *Grid) key_combinations() int {
+	colors := 0
+	for _, line := range grid.Cells {
+		for _, tile := range line {
+			if color, ok := key_color(tile); ok && color >= colors {
+				colors = color + 1
+			}
+		}
+	}
+	return 1 << colors
+}
@@ -0,0 +1,51 @@
+package Maze
+
+import "fmt"
+
+// ------------------------ Headless Mode ------------------------- //
+
+// Run the genetic algorithm without opening a window
+// Each step executes one cycle, the same way the PixelGL window does on each
+// frame, but at CPU speed and without needing an OpenGL context
+func RunHeadless(cfg Config) (*Simulation, error) {
+
+	// The headless mode just makes sense for the automation
+	cfg.Automation = true
+
+	// Generate the population, players and map
+	sim, err := NewSimulation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run all generations
+	sim.Run()
+
+	return sim, nil
+}
+
+// Run the genetic algorithm without a window and print the route of the best individual
+func Solve(cfg Config) error {
+	sim, err := RunHeadless(cfg)
+	if err != nil {
+		return err
+	}
+
+	best, ok := sim.Best()
+	if !ok {
+		fmt.Fprintf(sim.Config.Output, "No solution found in %d generations.\n", cfg.Generations)
+		return nil
+	}
+
+	// Translate the commands executed by the best individual into arrows
+	commands := best.Individual
+	arrows := map[Direction]string{up: "↑", down: "↓", left: "←", right: "→"}
+
+	fmt.Fprintf(sim.Config.Output, "Solution (generation %d, %d steps, best solution: %d):\n", best.Generation, best.Steps, sim.Grid.Best_solution)
+	for i := 0; i < best.Steps; i++ {
+		fmt.Fprint(sim.Config.Output, arrows[commands[i]])
+	}
+	fmt.Fprintln(sim.Config.Output)
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package Maze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ------------------------- Map Validation ----------------------- //
+
+// Problem of a map, Line and Column count from 1 (0 = the problem isn't on a
+// line or column)
+type MapIssue struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (issue MapIssue) String() string {
+	if issue.Line == 0 {
+		return issue.Message
+	} else if issue.Column == 0 {
+		return fmt.Sprintf("line %d: %s", issue.Line, issue.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", issue.Line, issue.Column, issue.Message)
+}
+
+// All the problems of a map, returned as the error of ParseMap
+type MapErrors []MapIssue
+
+func (issues MapErrors) Error() string {
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	return strings.Join(lines, "; ")
+}
+
+// Check a map file (name of the maps directory, path or Tiled map) and return all its
+// problems, the map can be played when there isn't any
+func LintMap(name string) MapErrors {
+	var err error
+
+	if path := map_path(name); tiled_map_file(path) {
+		_, err = ImportTiled(path)
+	} else {
+		file, open_err := os.Open(path)
+		if open_err != nil {
+			return MapErrors{{Message: open_err.Error()}}
+		}
+		defer file.Close()
+
+		_, err = ParseMap(file)
+	}
+
+	if err != nil {
+		if issues, ok := err.(MapErrors); ok {
+			return issues
+		}
+		return MapErrors{{Message: err.Error()}}
+	}
+	return nil
+}
+
+// Kind of the tiles that would let the players leave the map from the border
+// ("" for the trees and the hazards, they can't be crossed)
+func border_kind(tile uint8) string {
+	if _, ok := key_color(tile); ok {
+		return fmt.Sprintf("key '%c'", tile_char(tile))
+	} else if _, ok := door_color(tile); ok {
+		return fmt.Sprintf("door '%c'", tile_char(tile))
+	} else if _, ok := teleporter_pair(tile); ok {
+		return fmt.Sprintf("teleporter '%c'", tile_char(tile))
+	} else if _, ok := one_way_direction(tile); ok {
+		return fmt.Sprintf("one-way tile '%c'", tile_char(tile))
+	} else if tile == 0 {
+		return "path"
+	}
+	return ""
+}
+
+// Check the border, the start and the exits of a rectangular map. The
+// positions count the lines from the top, and the Line and Column of the
+// issues are the indexes of the cells (from 0)
+func validate_layout(cells [][]uint8, start Position, exits []Position) MapErrors {
+	var issues MapErrors
+
+	height, width := len(cells), len(cells[0])
+	openings := map[Position]bool{start: true}
+	for _, exit := range exits {
+		openings[exit] = true
+	}
+
+	// Just trees, the start and the exits on the border, so the players can't leave the map
+	for line := 0; line < height; line++ {
+		for column := 0; column < width; column++ {
+			border := line == 0 || line == height-1 || column == 0 || column == width-1
+			if kind := border_kind(cells[line][column]); border && kind != "" && !openings[Position{column, line}] {
+				issues = append(issues, MapIssue{line, column, fmt.Sprintf("%s on the border, it should be a tree, the start (S) or an exit (E)", kind)})
+			}
+		}
+	}
+
+	if cells[start.Y][start.X] != 0 {
+		issues = append(issues, MapIssue{start.Y, start.X, "the start isn't on a path"})
+	}
+
+	// Teleporters need exactly one partner
+	teleporters := make(map[int][]Position)
+	for line := 0; line < height; line++ {
+		for column := 0; column < width; column++ {
+			if pair, ok := teleporter_pair(cells[line][column]); ok {
+				teleporters[pair] = append(teleporters[pair], Position{column, line})
+			}
+		}
+	}
+	for pair := 0; pair < teleporter_pairs; pair++ {
+		if tiles := teleporters[pair]; len(tiles) != 0 && len(tiles) != 2 {
+			for _, pos := range tiles {
+				issues = append(issues, MapIssue{pos.Y, pos.X, fmt.Sprintf("the teleporter '%c' needs exactly 2 tiles, the map has %d", teleporter_chars[pair], len(tiles))})
+			}
+		}
+	}
+
+	// Cells that can be reached from the start, picking up the keys on the way
+	// and avoiding the hazards. The moves follow the rules of the players
+	// (step), on the players coordinates
+	layout := &Grid{Cells: cells}
+	type state struct {
+		pos  Position
+		keys Keys
+	}
+	reached := make([][]bool, height)
+	for line := range reached {
+		reached[line] = make([]bool, width)
+	}
+	first := state{Position{start.X, height - 1 - start.Y}, 0}
+	visited := map[state]bool{first: true}
+	queue := []state{first}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		reached[height-1-current.pos.Y][current.pos.X] = true
+
+		for direction := up; direction <= right; direction++ {
+			next, ok := layout.step(current.pos, direction, current.keys)
+			if !ok || is_hazard(layout.Tile(next.X, next.Y)) {
+				continue
+			}
+			if next_state := (state{next, current.keys.pick(layout.Tile(next.X, next.Y))}); !visited[next_state] {
+				visited[next_state] = true
+				queue = append(queue, next_state)
+			}
+		}
+	}
+
+	for _, exit := range exits {
+		if cells[exit.Y][exit.X] != 0 {
+			issues = append(issues, MapIssue{exit.Y, exit.X, "the exit isn't on a path"})
+		} else if !reached[exit.Y][exit.X] {
+			issues = append(issues, MapIssue{exit.Y, exit.X, "the exit can't be reached from the start"})
+		}
+	}
+
+	return issues
+}
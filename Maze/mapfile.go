@@ -0,0 +1,381 @@
+package Maze
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --------------------------- Map Files -------------------------- //
+
+// Directory of the maps that can be selected by name ([Maps] map=1 loads maps/1.map)
+var Maps_dir = "maps"
+
+// Tiles of the map files
+//
+//	. or 0   path
+//	1 - 4    trees (light green, pink, dark green and middle green)
+//	#        wall (drawn as the light green tree)
+//	S        start (path)
+//	E        exit (path)
+//	a - d    keys, picked up when the player enters the tile
+//	A - D    doors, open for the players that have the key of the same letter
+//	~ % ^    hazards (water, lava and spikes), the players that enter them die
+//	w - z    teleporters, each letter on 2 tiles moves the player to the other one
+//	n v < >  one-way tiles (up, down, left and right), entered and left just on their direction
+//	@enemy   enemy walking through the tiles of the line (@enemy 3,2 9,2), not a tile
+//	;        comment until the end of the line
+var map_tiles = map[rune]uint8{
+	'.': 0, '0': 0, 'S': 0, 'E': 0,
+	'1': 1, '2': 2, '3': 3, '4': 4,
+	'#': 1,
+	'a': key_tile, 'b': key_tile + 1, 'c': key_tile + 2, 'd': key_tile + 3,
+	'A': door_tile, 'B': door_tile + 1, 'C': door_tile + 2, 'D': door_tile + 3,
+	'~': hazard_tile, '%': hazard_tile + 1, '^': hazard_tile + 2,
+	'w': teleporter_tile, 'x': teleporter_tile + 1, 'y': teleporter_tile + 2, 'z': teleporter_tile + 3,
+	'n': one_way_tile + uint8(up), 'v': one_way_tile + uint8(down), '<': one_way_tile + uint8(left), '>': one_way_tile + uint8(right),
+}
+
+// Characters of the hazards, the teleporters and the one-way tiles (by
+// direction) on the map files
+const (
+	hazard_chars     = "~%^"
+	teleporter_chars = "wxyz"
+	one_way_chars    = "nv<>"
+)
+
+// Character of the tile on the map files ('.' for the path)
+func tile_char(tile uint8) byte {
+	if tile == 0 {
+		return '.'
+	} else if color, ok := key_color(tile); ok {
+		return byte('a' + color)
+	} else if color, ok := door_color(tile); ok {
+		return byte('A' + color)
+	} else if is_hazard(tile) {
+		return hazard_chars[tile-hazard_tile]
+	} else if pair, ok := teleporter_pair(tile); ok {
+		return teleporter_chars[pair]
+	} else if direction, ok := one_way_direction(tile); ok {
+		return one_way_chars[direction]
+	}
+	return '0' + tile
+}
+
+// Extensions of the maps searched on the maps directory, the map files and
+// the maps of Tiled
+var map_extensions = []string{".map", ".tmx", ".tmj"}
+
+// File of a map name: a name without extension is searched on the maps directory
+func map_path(name string) string {
+	if strings.ContainsAny(name, `/\`) || filepath.Ext(name) != "" {
+		return name
+	}
+	for _, extension := range map_extensions {
+		if path := filepath.Join(Maps_dir, name+extension); file_exists(path) {
+			return path
+		}
+	}
+	return filepath.Join(Maps_dir, name+".map")
+}
+
+func file_exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Load a map by its name (maps directory), file path (map file or Tiled map)
+// or generator (random:prim:30x20:seed=42)
+func LoadMap(name string) (*Grid, error) {
+	if strings.HasPrefix(name, "random:") {
+		grid, seed, err := GenerateMap(name)
+		if err != nil {
+			return nil, err
+		}
+		grid.Map_seed = seed
+		return grid, nil
+	}
+	if path := map_path(name); tiled_map_file(path) {
+		grid, err := ImportTiled(path)
+		if err != nil {
+			return nil, fmt.Errorf("map '%s': %w", name, err)
+		}
+		return grid, nil
+	}
+
+	file, err := os.Open(map_path(name))
+	if err != nil {
+		return nil, fmt.Errorf("map '%s' not found: %w", name, err)
+	}
+	defer file.Close()
+
+	grid, err := ParseMap(file)
+	if err != nil {
+		return nil, fmt.Errorf("map '%s': %w", name, err)
+	}
+
+	return grid, nil
+}
+
+// Tiles, markers and enemies of a map file, before the layout is validated
+type map_file struct {
+	lines  [][]uint8
+	starts []Position
+	exits  []Position
+
+	// Waypoints of the enemies (@enemy) and their lines of the file
+	enemies     [][]Position
+	enemy_lines []int
+
+	// Line of the file and column of the first tile of each line of the grid
+	line_numbers  []int
+	first_columns []int
+}
+
+// Read a map on the text format, one line of tiles for each line of the grid
+// The problems of the map are returned as MapErrors, with their lines and columns
+func ParseMap(r io.Reader) (*Grid, error) {
+	var issues MapErrors
+
+	m, err := read_map(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.starts) == 0 {
+		issues = append(issues, MapIssue{Message: "the map needs one start (S)"})
+	}
+	for i := 1; i < len(m.starts); i++ {
+		issues = append(issues, MapIssue{m.line_numbers[m.starts[i].Y], m.first_columns[m.starts[i].Y] + m.starts[i].X, "another start (S), the map needs just one"})
+	}
+	if len(m.exits) == 0 {
+		issues = append(issues, MapIssue{Message: "the map needs at least one exit (E)"})
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	// Border, start and exits, on the lines and columns of the file
+	issues = validate_layout(m.lines, m.starts[0], m.exits)
+	for i := range issues {
+		issues[i].Line, issues[i].Column = m.line_numbers[issues[i].Line], m.first_columns[issues[i].Line]+issues[i].Column
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	// The layout was validated above
+	grid := build_grid(m.lines, m.starts[0], m.exits)
+	for i, waypoints := range m.enemies {
+		if err := grid.add_enemy(waypoints); err != nil {
+			issues = append(issues, MapIssue{m.enemy_lines[i], 0, err.Error()})
+		}
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+	return grid, nil
+}
+
+// Read the tiles and the directives of a map file, the unknown tiles, ragged
+// lines and wrong directives are returned as MapErrors
+func read_map(r io.Reader) (*map_file, error) {
+	var (
+		m      map_file
+		issues MapErrors
+	)
+
+	scanner := bufio.NewScanner(r)
+	line_number := 0
+	for scanner.Scan() {
+		line_number++
+
+		// Remove the comments
+		line := scanner.Text()
+		if comment := strings.IndexRune(line, ';'); comment >= 0 {
+			line = line[:comment]
+		}
+		first_column := len(line) - len(strings.TrimLeft(line, " \t")) + 1
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Directives, on any line of the file
+		if strings.HasPrefix(line, "@") {
+			fields := strings.Fields(line)
+			if fields[0] != "@enemy" {
+				issues = append(issues, MapIssue{line_number, first_column, fmt.Sprintf("unknown directive '%s' (options: @enemy)", fields[0])})
+				continue
+			}
+			waypoints, err := parse_enemy(fields[1:])
+			if err != nil {
+				issues = append(issues, MapIssue{line_number, first_column, err.Error()})
+				continue
+			}
+			m.enemies = append(m.enemies, waypoints)
+			m.enemy_lines = append(m.enemy_lines, line_number)
+			continue
+		}
+
+		var (
+			cells                   []uint8
+			line_starts, line_exits []Position
+		)
+		for column, char := range []rune(line) {
+			tile, ok := map_tiles[char]
+			if !ok {
+				issues = append(issues, MapIssue{line_number, first_column + column, fmt.Sprintf("unknown tile '%c'", char)})
+			}
+
+			// The lines are counted from the top, the Y of the positions is fixed after reading all lines
+			if char == 'S' {
+				line_starts = append(line_starts, Position{column, len(m.lines)})
+			} else if char == 'E' {
+				line_exits = append(line_exits, Position{column, len(m.lines)})
+			}
+			cells = append(cells, tile)
+		}
+
+		// Column where the line diverges: after its last tile when it's short, or its first extra tile
+		if len(m.lines) > 0 && len(cells) != len(m.lines[0]) {
+			column := len(cells)
+			if column > len(m.lines[0]) {
+				column = len(m.lines[0])
+			}
+			issues = append(issues, MapIssue{line_number, first_column + column, fmt.Sprintf("%d tiles, the first line of the map has %d", len(cells), len(m.lines[0]))})
+			continue
+		}
+		m.starts = append(m.starts, line_starts...)
+		m.exits = append(m.exits, line_exits...)
+		m.lines = append(m.lines, cells)
+		m.line_numbers = append(m.line_numbers, line_number)
+		m.first_columns = append(m.first_columns, first_column)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(m.lines) == 0 {
+		return nil, MapErrors{{Message: "the map is empty"}}
+	}
+	if len(issues) > 0 {
+		return nil, issues // The start and exits of the wrong lines weren't read
+	}
+	return &m, nil
+}
+
+// Grid of the map file without validating the layout, to be fixed on the
+// editor. A missing start is on -1, -1 (like on the editor) and the enemies
+// with a wrong route stand on their first tile
+func (m *map_file) unchecked_grid() *Grid {
+	height := len(m.lines)
+	grid := &Grid{Cells: m.lines, Start: Position{-1, -1}}
+
+	// Players coordinates count the Y axis from the bottom
+	if len(m.starts) > 0 {
+		grid.Start = Position{m.starts[0].X, height - 1 - m.starts[0].Y}
+	}
+	for _, exit := range m.exits {
+		grid.Exits = append(grid.Exits, Position{exit.X, height - 1 - exit.Y})
+	}
+
+	for _, waypoints := range m.enemies {
+		if grid.add_enemy(waypoints) == nil {
+			continue
+		}
+		patrol := Patrol{}
+		for _, waypoint := range waypoints {
+			patrol.Waypoints = append(patrol.Waypoints, Position{waypoint.X, height - 1 - waypoint.Y})
+		}
+		patrol.Route = patrol.Waypoints[:1]
+		grid.Enemies = append(grid.Enemies, patrol)
+	}
+	return grid
+}
+
+// Write the map on the text format, with the same header of the maps directory
+func (grid *Grid) WriteMap(w io.Writer, title string) error {
+	var text strings.Builder
+
+	fmt.Fprintf(&text, "; %s - %d x %d\n;\n", title, grid.Width(), grid.Height())
+	fmt.Fprintf(&text, "; .  path\t\t1  light green tree\t2  pink tree\n")
+	fmt.Fprintf(&text, "; S  start\t\t3  dark green tree\t4  middle green tree\n")
+	fmt.Fprintf(&text, "; E  exit\n")
+	if grid.key_combinations() > 1 {
+		fmt.Fprintf(&text, "; a - d  keys\t\tA - D  doors of the keys\n")
+	}
+	if grid.has_hazards() {
+		fmt.Fprintf(&text, "; ~  water\t\t%%  lava\t\t\t^  spikes\n")
+	}
+	moves := false
+	for _, line := range grid.Cells {
+		for _, tile := range line {
+			_, teleporter := teleporter_pair(tile)
+			_, one_way := one_way_direction(tile)
+			moves = moves || teleporter || one_way
+		}
+	}
+	if moves {
+		fmt.Fprintf(&text, "; w - z  teleporters (pairs)\tn v < >  one-way (up, down, left, right)\n")
+	}
+
+	for y := grid.Height() - 1; y >= 0; y-- {
+		for x := 0; x < grid.Width(); x++ {
+			if pos := (Position{x, y}); pos == grid.Start {
+				text.WriteByte('S')
+			} else if grid.is_exit(pos) {
+				text.WriteByte('E')
+			} else {
+				text.WriteByte(tile_char(grid.Tile(x, y)))
+			}
+		}
+		text.WriteByte('\n')
+	}
+
+	// Enemies, with the column and line of the map counting from 1
+	for i, waypoints := range grid.enemy_waypoints() {
+		if i == 0 {
+			fmt.Fprintf(&text, "\n; Enemies: @enemy <column>,<line> ...\n")
+		}
+		text.WriteString("@enemy")
+		for _, waypoint := range waypoints {
+			fmt.Fprintf(&text, " %d,%d", waypoint.X+1, waypoint.Y+1)
+		}
+		text.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, text.String())
+	return err
+}
+
+// Grid of the cells, with the start and the exits counting the lines from the
+// top (like the cells). Refuses the grid if its layout isn't valid
+func new_grid(cells [][]uint8, start Position, exits []Position) (*Grid, error) {
+	if issues := validate_layout(cells, start, exits); len(issues) > 0 {
+		for i := range issues {
+			issues[i].Line++
+			issues[i].Column++
+		}
+		return nil, issues
+	}
+	return build_grid(cells, start, exits), nil
+}
+
+// Grid of cells already validated (like new_grid)
+func build_grid(cells [][]uint8, start Position, exits []Position) *Grid {
+	grid := &Grid{Cells: cells}
+
+	// Players coordinates count the Y axis from the bottom
+	grid.Start = Position{start.X, len(cells) - 1 - start.Y}
+	for _, exit := range exits {
+		grid.Exits = append(grid.Exits, Position{exit.X, len(cells) - 1 - exit.Y})
+	}
+
+	// Steps of the shortest route (the exits are reachable)
+	grid.Best_solution = grid.Distance(grid.Start.X, grid.Start.Y, 0)
+
+	return grid
+}
@@ -0,0 +1,125 @@
+package Maze
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Problems of the map files, with the lines and columns of the file (from 1)
+
+func TestParseMapIssues(t *testing.T) {
+	tests := []struct {
+		name   string
+		maze   string
+		issues MapErrors
+	}{
+		{"ragged lines", "" +
+			"11111\n" +
+			"S...E\n" +
+			"1111\n",
+			MapErrors{{3, 5, "4 tiles, the first line of the map has 5"}}},
+		{"long line", "" +
+			"11111\n" +
+			"  S...E11\n" +
+			"11111\n",
+			MapErrors{{2, 8, "7 tiles, the first line of the map has 5"}}},
+		{"unknown tile", "" +
+			"11111\n" +
+			"S.q.E\n" +
+			"11111\n",
+			MapErrors{{2, 3, "unknown tile 'q'"}}},
+		{"second start", "" +
+			"11111\n" +
+			"S...E\n" +
+			"1.S.1\n" +
+			"11111\n",
+			MapErrors{{3, 3, "another start (S), the map needs just one"}}},
+		{"missing exit", "" +
+			"11111\n" +
+			"S...1\n" +
+			"11111\n",
+			MapErrors{{0, 0, "the map needs at least one exit (E)"}}},
+		{"unreachable exit", "" +
+			"; The trees close the exit\n" +
+			"1111111\n" +
+			"S..1..E\n" +
+			"1111111\n",
+			MapErrors{{3, 7, "the exit can't be reached from the start"}}},
+		{"path on the border", "" +
+			"11.11\n" +
+			"S...E\n" +
+			"11111\n",
+			MapErrors{{1, 3, "path on the border, it should be a tree, the start (S) or an exit (E)"}}},
+		{"door and one-way tile on the border", "" +
+			"11A11\n" +
+			"S...E\n" +
+			"1>111\n",
+			MapErrors{
+				{1, 3, "door 'A' on the border, it should be a tree, the start (S) or an exit (E)"},
+				{3, 2, "one-way tile '>' on the border, it should be a tree, the start (S) or an exit (E)"},
+			}},
+		{"enemy crossing the start", "" +
+			"1111111\n" +
+			"S.....E\n" +
+			"1111111\n" +
+			"@enemy 3,2 1,2\n",
+			MapErrors{{4, 0, "enemy: the route crosses the start (S) on the tile 1,2"}}},
+		{"leading whitespace", "" +
+			"  11111\n" +
+			"\tS.1.E\n" +
+			"  11111\n",
+			MapErrors{{2, 6, "the exit can't be reached from the start"}}},
+		{"leading whitespace and unknown tile", "" +
+			"   11111\n" +
+			"   S.?.E\n" +
+			"   11111\n",
+			MapErrors{{2, 6, "unknown tile '?'"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseMap(strings.NewReader(test.maze))
+			issues, ok := err.(MapErrors)
+			if !ok {
+				t.Fatalf("expected MapErrors, got %v", err)
+			}
+			if !reflect.DeepEqual(issues, test.issues) {
+				t.Errorf("issues:\n%v\nexpected:\n%v", issues, test.issues)
+			}
+		})
+	}
+}
+
+// The editor opens the maps with problems on the layout, to fix them
+func TestUncheckedGrid(t *testing.T) {
+	maze := "" +
+		"1111111\n" +
+		"..1w1.E\n" +
+		"1111111\n" +
+		"@enemy 2,2 6,2\n"
+
+	if _, err := ParseMap(strings.NewReader(maze)); err == nil {
+		t.Fatal("the map with problems was accepted")
+	}
+	m, err := read_map(strings.NewReader(maze))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid := m.unchecked_grid()
+	if grid.Width() != 7 || grid.Height() != 3 || grid.Tile(3, 1) != teleporter_tile {
+		t.Errorf("wrong cells: %v", grid.Cells)
+	}
+	if grid.Start != (Position{-1, -1}) || !reflect.DeepEqual(grid.Exits, []Position{{6, 1}}) {
+		t.Errorf("start %v and exits %v, expected -1,-1 and [6,1]", grid.Start, grid.Exits)
+	}
+
+	// The enemy walks through the trees, but it's kept to be fixed
+	if len(grid.Enemies) != 1 || !reflect.DeepEqual(grid.enemy_waypoints(), [][]Position{{{1, 1}, {5, 1}}}) {
+		t.Errorf("enemies %v, expected the waypoints [1,1 5,1]", grid.Enemies)
+	}
+	if grid.Enemies[0].At(3) != (Position{1, 1}) {
+		t.Errorf("the enemy with a wrong route should stand on its first tile, it's on %v", grid.Enemies[0].At(3))
+	}
+}
@@ -1,147 +0,0 @@
-package Maze
-
-var (
-	Maze_map int
-
-	// Background
-	// 0 = path
-	// 1 = light green tree		2 = pink tree
-	// 3 = dark green tree		4 = middle green tree
-
-	// ------------------ Map 0 ------------------ //
-	// 15 x 10 Empty
-	backgroundMap_0 [][]uint8 = [][]uint8{
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	// 15 x 10 Empty (+3 for debug scren)
-	backgroundMap_0_automate [][]uint8 = [][]uint8{
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	backgroundMap_0_best_solution = 20
-
-	// ------------------ Map 1 ------------------ //
-	// 15 x 10
-	backgroundMap_1 [][]uint8 = [][]uint8{
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 3, 0, 0, 0, 0, 0, 2, 3, 1, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 4, 3, 2, 0, 0, 0, 1},
-		{1, 0, 0, 0, 4, 1, 3, 0, 0, 1, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 2, 2, 1, 0, 0, 0, 0, 0, 2, 0, 1},
-		{1, 1, 0, 0, 1, 3, 2, 0, 0, 0, 0, 0, 4, 0, 1},
-		{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 2, 3, 0, 1},
-		{1, 0, 1, 3, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0},
-		{1, 0, 2, 4, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	// 15 x 10 (+3 for debug scren)
-	backgroundMap_1_automate [][]uint8 = [][]uint8{
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 3, 0, 0, 0, 0, 0, 2, 3, 1, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 4, 3, 2, 0, 0, 0, 1},
-		{1, 0, 0, 0, 4, 1, 3, 0, 0, 1, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 2, 2, 1, 0, 0, 0, 0, 0, 2, 0, 1},
-		{1, 1, 0, 0, 1, 3, 2, 0, 0, 0, 0, 0, 4, 0, 1},
-		{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 2, 3, 0, 1},
-		{1, 0, 1, 3, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0},
-		{1, 0, 2, 4, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	backgroundMap_1_best_solution = 19
-
-	// ------------------ Map 2 ------------------ //
-	// 10 x 10
-	backgroundMap_2 [][]uint8 = [][]uint8{
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 2, 0, 0, 0, 0, 2, 0, 1},
-		{0, 0, 0, 0, 1, 0, 0, 3, 0, 1},
-		{1, 0, 0, 0, 3, 0, 0, 0, 0, 1},
-		{1, 0, 0, 2, 4, 2, 0, 0, 0, 1},
-		{1, 0, 0, 0, 2, 0, 0, 0, 0, 0},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 1, 0, 0, 0, 0, 3, 2, 1},
-		{1, 0, 1, 0, 0, 0, 0, 3, 2, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	// 10 x 10 (+3 for debug scren)
-	backgroundMap_2_automate [][]uint8 = [][]uint8{
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 2, 0, 0, 0, 0, 2, 0, 1},
-		{0, 0, 0, 0, 1, 0, 0, 3, 0, 1},
-		{1, 0, 0, 0, 3, 0, 0, 0, 0, 1},
-		{1, 0, 0, 2, 4, 2, 0, 0, 0, 1},
-		{1, 0, 0, 0, 2, 0, 0, 0, 0, 0},
-		{1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 1, 0, 0, 0, 0, 3, 2, 1},
-		{1, 0, 1, 0, 0, 0, 0, 3, 2, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	backgroundMap_2_best_solution = 14
-
-	// ------------------ Map 3 ------------------ //
-	// 20 x 10
-	backgroundMap_3 [][]uint8 = [][]uint8{
-
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 3, 0, 0, 0, 0, 0, 2, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 4, 3, 2, 0, 0, 0, 0, 0, 1, 0, 0, 1},
-		{1, 0, 0, 0, 4, 1, 3, 0, 0, 1, 0, 0, 0, 0, 0, 2, 3, 4, 0, 1},
-		{1, 0, 0, 0, 2, 2, 1, 0, 0, 0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 1},
-		{1, 1, 0, 0, 1, 3, 2, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 2, 3, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 1, 3, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
-		{1, 0, 2, 4, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	// 20 x 10 (+3 for debug scren)
-	backgroundMap_3_automate [][]uint8 = [][]uint8{
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-		{1, 0, 3, 0, 0, 0, 0, 0, 2, 3, 1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{0, 0, 0, 0, 0, 0, 0, 0, 4, 3, 2, 0, 0, 0, 0, 0, 1, 0, 0, 1},
-		{1, 0, 0, 0, 4, 1, 3, 0, 0, 1, 0, 0, 0, 0, 0, 2, 3, 4, 0, 1},
-		{1, 0, 0, 0, 2, 2, 1, 0, 0, 0, 0, 0, 2, 0, 0, 0, 2, 0, 0, 1},
-		{1, 1, 0, 0, 1, 3, 2, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 2, 3, 0, 0, 0, 0, 0, 0, 1},
-		{1, 0, 1, 3, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0},
-		{1, 0, 2, 4, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
-		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
-	}
-
-	backgroundMap_3_best_solution = 26
-)
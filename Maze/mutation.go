@@ -0,0 +1,226 @@
+package Maze
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ----------------------- Mutation Operators --------------------- //
+
+// Change the individual on the mutated gene. The command operators work with
+// the command (pair of genes) that contains the gene
+type Mutation interface {
+	Mutate(rng *rand.Rand, individual Genome, gene int)
+}
+
+// The flip is the only operator that changes a single gene, the others are
+// applied once per command
+func gene_operator(operator Mutation) bool {
+	_, flip := operator.(FlipMutation)
+	return flip
+}
+
+// Built-in mutation operators, used on the INI file ([Settings] Mutation_mix)
+var mutation_operators = map[string]Mutation{
+	"flip":     FlipMutation{},
+	"replace":  ReplaceMutation{},
+	"swap":     SwapMutation{},
+	"insert":   InsertMutation{},
+	"delete":   DeleteMutation{},
+	"scramble": ScrambleMutation{Length: 4},
+}
+
+// Names of the built-in mutation operators
+func MutationNames() []string {
+	var names []string
+	for name := range mutation_operators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ---------------------------- Mix ------------------------------- //
+
+// Operator of the mix and its weight (chance of being chosen)
+type MutationWeight struct {
+	Name     string
+	Operator Mutation
+	Weight   float64
+}
+
+// Operators used on each mutation, one of them is chosen accordingly to the weights
+type MutationMix []MutationWeight
+
+// Read a mix like "flip:2,swap:1,scramble" (the weight is 1 when it's not informed)
+func ParseMutationMix(text string) (MutationMix, error) {
+	var mix MutationMix
+
+	for _, item := range strings.Split(text, ",") {
+		name, weight_text, has_weight := strings.Cut(strings.TrimSpace(item), ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		operator, ok := mutation_operators[name]
+		if !ok {
+			return nil, fmt.Errorf("mutation operator '%s' not found (options: %s)", name, strings.Join(MutationNames(), ", "))
+		}
+
+		weight := 1.0
+		if has_weight {
+			var err error
+			weight, err = strconv.ParseFloat(strings.TrimSpace(weight_text), 64)
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight of mutation operator '%s': %s", name, weight_text)
+			}
+		}
+
+		mix = append(mix, MutationWeight{Name: name, Operator: operator, Weight: weight})
+	}
+
+	return mix, nil
+}
+
+// Percentage of each operator, like "flip 67% swap 33%"
+func (mix MutationMix) String() string {
+	var (
+		total float64
+		parts []string
+	)
+
+	for _, item := range mix {
+		total += item.Weight
+	}
+	for _, item := range mix {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", item.Name, item.Weight*100/total))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Choose one operator (no random number is used when there is just one)
+func (mix MutationMix) choose(rng *rand.Rand) Mutation {
+	if len(mix) == 1 {
+		return mix[0].Operator
+	}
+
+	var total float64
+	for _, item := range mix {
+		total += item.Weight
+	}
+
+	target := rng.Float64() * total
+	for _, item := range mix {
+		target -= item.Weight
+		if target < 0 {
+			return item.Operator
+		}
+	}
+	return mix[len(mix)-1].Operator
+}
+
+// --------------------------- Adaptive --------------------------- //
+
+// Raise the mutation rate (multiplying by Factor, until Max_rate) when the best
+// score doesn't improve for Stagnation generations, and lower it (dividing by
+// Factor, until the configured Mutation_rate) when the best score improves
+// Stagnation = 0 disables it
+type AdaptiveMutation struct {
+	Stagnation int
+	Factor     float64
+	Max_rate   float64
+}
+
+// Mutation rate of the next generation
+func (adaptive AdaptiveMutation) next_rate(rate float64, base_rate float64, improved bool, stagnant_generations int) float64 {
+	if adaptive.Stagnation <= 0 {
+		return rate
+	}
+
+	if improved {
+		rate = rate / adaptive.Factor
+		if rate < base_rate {
+			rate = base_rate
+		}
+	} else if stagnant_generations > 0 && stagnant_generations%adaptive.Stagnation == 0 {
+		rate = rate * adaptive.Factor
+		if rate > adaptive.Max_rate {
+			rate = adaptive.Max_rate
+		}
+	}
+	return rate
+}
+
+// ---------------------------- Flip ------------------------------ //
+
+// Invert the gene (original mutation)
+type FlipMutation struct{}
+
+func (FlipMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	individual[gene/2] ^= gene_mask(gene)
+}
+
+// --------------------------- Replace ---------------------------- //
+
+// Replace the command by a random one
+type ReplaceMutation struct{}
+
+func (ReplaceMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	individual[gene/2] = Direction(rng.Intn(4))
+}
+
+// ---------------------------- Swap ------------------------------ //
+
+// Swap the command with another random command
+type SwapMutation struct{}
+
+func (SwapMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	other := rng.Intn(len(individual))
+	individual[gene/2], individual[other] = individual[other], individual[gene/2]
+}
+
+// --------------------------- Insert ----------------------------- //
+
+// Insert a random command, the next commands are shifted to the end (the last one is lost)
+type InsertMutation struct{}
+
+func (InsertMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	command := gene / 2
+	copy(individual[command+1:], individual[command:len(individual)-1])
+	individual[command] = Direction(rng.Intn(4))
+}
+
+// --------------------------- Delete ----------------------------- //
+
+// Delete the command, the next commands are shifted to the start and a random
+// command is added on the end
+type DeleteMutation struct{}
+
+func (DeleteMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	command := gene / 2
+	copy(individual[command:], individual[command+1:])
+	individual[len(individual)-1] = Direction(rng.Intn(4))
+}
+
+// -------------------------- Scramble ---------------------------- //
+
+// Shuffle the commands of a segment (up to Length commands) starting on the command
+type ScrambleMutation struct {
+	Length int
+}
+
+func (m ScrambleMutation) Mutate(rng *rand.Rand, individual Genome, gene int) {
+	command := gene / 2
+	end := command + m.Length
+	if end > len(individual) {
+		end = len(individual)
+	}
+
+	// Fisher-Yates
+	segment := individual[command:end]
+	for i := len(segment) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		segment[i], segment[j] = segment[j], segment[i]
+	}
+}
@@ -0,0 +1,122 @@
+package Maze
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// The insert and the delete shift the commands after the mutated one, the
+// adaptive rate stays between the configured rate and the maximum
+
+func TestInsertMutation(t *testing.T) {
+	tests := []struct {
+		name     string
+		gene     int
+		expected Genome // The inserted command is checked apart
+	}{
+		{"first command", 0, Genome{0, up, down, left, right}},
+		{"second gene of a command", 3, Genome{up, 0, down, left, right}},
+		{"last command", 8, Genome{up, down, left, right, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			individual := Genome{up, down, left, right, up}
+			InsertMutation{}.Mutate(rand.New(rand.NewSource(1)), individual, test.gene)
+
+			command := test.gene / 2
+			if individual[command] > right {
+				t.Errorf("invalid command inserted: %d", individual[command])
+			}
+			individual[command] = 0
+			if !reflect.DeepEqual(individual, test.expected) {
+				t.Errorf("got %v, expected %v", individual, test.expected)
+			}
+		})
+	}
+}
+
+func TestDeleteMutation(t *testing.T) {
+	tests := []struct {
+		name     string
+		gene     int
+		expected Genome // The command added on the end is checked apart
+	}{
+		{"first command", 1, Genome{down, left, right, up, 0}},
+		{"middle command", 4, Genome{up, down, right, up, 0}},
+		{"last command", 9, Genome{up, down, left, right, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			individual := Genome{up, down, left, right, up}
+			DeleteMutation{}.Mutate(rand.New(rand.NewSource(1)), individual, test.gene)
+
+			last := len(individual) - 1
+			if individual[last] > right {
+				t.Errorf("invalid command added: %d", individual[last])
+			}
+			individual[last] = 0
+			if !reflect.DeepEqual(individual, test.expected) {
+				t.Errorf("got %v, expected %v", individual, test.expected)
+			}
+		})
+	}
+}
+
+func TestAdaptiveNextRate(t *testing.T) {
+	adaptive := AdaptiveMutation{Stagnation: 3, Factor: 2, Max_rate: 0.3}
+
+	tests := []struct {
+		name      string
+		adaptive  AdaptiveMutation
+		rate      float64
+		improved  bool
+		stagnant  int
+		next_rate float64
+	}{
+		{"disabled", AdaptiveMutation{}, 0.1, false, 3, 0.1},
+		{"stagnation raises the rate", adaptive, 0.05, false, 3, 0.1},
+		{"raised until the maximum", adaptive, 0.2, false, 6, 0.3},
+		{"the maximum isn't passed", adaptive, 0.3, false, 9, 0.3},
+		{"between the stagnation generations", adaptive, 0.1, false, 4, 0.1},
+		{"improvement lowers the rate", adaptive, 0.2, true, 0, 0.1},
+		{"lowered until the configured rate", adaptive, 0.08, true, 0, 0.05},
+		{"the configured rate isn't passed", adaptive, 0.05, true, 0, 0.05},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if rate := test.adaptive.next_rate(test.rate, 0.05, test.improved, test.stagnant); rate != test.next_rate {
+				t.Errorf("rate %g, expected %g", rate, test.next_rate)
+			}
+		})
+	}
+}
+
+// With Mutation_rate = 1 the flip changes every gene and the command
+// operators every command (once, not once per gene)
+func TestMutationCounts(t *testing.T) {
+	for _, test := range []struct {
+		mix      string
+		genes    int
+		commands int
+	}{
+		{"flip", 20, 0},
+		{"replace", 0, 10},
+		{"insert", 0, 10},
+		{"scramble", 0, 10},
+	} {
+		mix, err := ParseMutationMix(test.mix)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pop := Population{make(Genome, 10)}
+		genes, commands, individuals := generate_mutation(rand.New(rand.NewSource(1)), mix, pop, 1)
+		if genes != test.genes || commands != test.commands || individuals != 1 {
+			t.Errorf("%s: %d genes and %d commands (%d individuals), expected %d genes and %d commands", test.mix, genes, commands, individuals, test.genes, test.commands)
+		}
+	}
+}
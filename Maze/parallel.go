@@ -0,0 +1,73 @@
+package Maze
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ---------------------- Parallel Evaluation --------------------- //
+
+// Execute all commands of the population, each worker moves its own players
+// and calculates their scores. The shared results (objectives and maximum
+// position) are collected after the workers finish, in the same order that
+// the cycle by cycle execution (Step) records them, so the results don't
+// depend on the number of workers
+func (sim *Simulation) evaluate_parallel() {
+	pop_size := len(sim.Population)
+
+	workers := sim.Config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > pop_size {
+		workers = pop_size
+	}
+
+	scores := make([]int, pop_size)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+
+			// Worker N evaluates the individuals N, N + workers, N + 2 * workers...
+			for i := worker; i < pop_size; i += workers {
+				object := sim.players[i]
+				object.trace.Commands = len(sim.Population[i])
+
+				for step, direction := range sim.Population[i] {
+					object.move(sim.Grid, direction, step+1, sim.Config.Enemy_collision)
+				}
+
+				scores[i] = run_score(sim.Config.Fitness, sim.Grid, &object.trace)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	// Objectives ordered by the step when they were reached (the first
+	// individual on ties), the same order of the cycle by cycle execution
+	var reached []int
+	for i := 0; i < pop_size; i++ {
+		trace := &sim.players[i].trace
+		for step := 1; step < len(trace.Path); step++ { // The start isn't a move
+			sim.record_distance(trace.Path[step], trace.keys(step))
+		}
+		if trace.Reached_step > 0 {
+			reached = append(reached, i)
+		}
+	}
+	sort.SliceStable(reached, func(a, b int) bool {
+		return sim.players[reached[a]].trace.Reached_step < sim.players[reached[b]].trace.Reached_step
+	})
+
+	for _, i := range reached {
+		trace := &sim.players[i].trace
+		sim.objective = append(sim.objective, Result{Generation: sim.current_generation, Individual: sim.Population[i].Clone(), Exit: trace.Path[trace.Reached_step], Steps: trace.Reached_step})
+	}
+
+	sim.cycle = len(sim.Population[0])
+	sim.population_score = scores
+}
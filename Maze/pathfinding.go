@@ -0,0 +1,164 @@
+package Maze
+
+// ------------------------- Pathfinding -------------------------- //
+
+// Position on the grid, using the players coordinates (Y from the bottom)
+type Position struct {
+	X int
+	Y int
+}
+
+// Neighbour of a position on each direction
+func (pos Position) move(direction Direction) Position {
+	if direction == up {
+		return Position{pos.X, pos.Y + 1}
+	} else if direction == down {
+		return Position{pos.X, pos.Y - 1}
+	} else if direction == left {
+		return Position{pos.X - 1, pos.Y}
+	}
+	return Position{pos.X + 1, pos.Y}
+}
+
+// Position after a move from the position with the keys held, false if the
+// player can't move there. The one-way tiles are just entered and left on
+// their direction, and the teleporters move the player to their partner
+func (grid *Grid) step(pos Position, direction Direction, keys Keys) (Position, bool) {
+	next := pos.move(direction)
+	if !grid.Walkable(next.X, next.Y, keys) {
+		return pos, false
+	}
+	if one_way, ok := one_way_direction(grid.Tile(pos.X, pos.Y)); ok && one_way != direction {
+		return pos, false
+	}
+	if one_way, ok := one_way_direction(grid.Tile(next.X, next.Y)); ok && one_way != direction {
+		return pos, false
+	}
+
+	if partner, ok := grid.teleporters()[next]; ok {
+		return partner, true
+	}
+	return next, true
+}
+
+// Positions that complete the maze (the exits of the map file)
+func (grid *Grid) exits() []Position {
+	return grid.Exits
+}
+
+// Check if the position completes the maze
+func (grid *Grid) is_exit(pos Position) bool {
+	for _, exit := range grid.Exits {
+		if exit == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// Number of steps from each position to the nearest exit (distances[keys][y][x]),
+// for each combination of keys held, so the routes that need a key pass through
+// it. The routes avoid the hazards
+// Breadth-first search starting from all the exits and going back through the
+// moves of the players (step), so the one-way tiles and the teleporters are
+// followed the same way. -1 means there isn't a route
+func (grid *Grid) exit_distances() [][][]int {
+	grid.distances_once.Do(func() {
+		grid.distances = make([][][]int, grid.key_combinations())
+		for keys := range grid.distances {
+			grid.distances[keys] = make([][]int, grid.Height())
+			for y := range grid.distances[keys] {
+				grid.distances[keys][y] = make([]int, grid.Width())
+				for x := range grid.distances[keys][y] {
+					grid.distances[keys][y][x] = -1
+				}
+			}
+		}
+
+		type state struct {
+			pos  Position
+			keys Keys
+		}
+
+		// States that reach each state with one move. The players are never
+		// on a key without holding it, or on a hazard (they die there)
+		previous := make(map[state][]state)
+		for keys := range grid.distances {
+			for y := 0; y < grid.Height(); y++ {
+				for x := 0; x < grid.Width(); x++ {
+					current, tile := state{Position{x, y}, Keys(keys)}, grid.Tile(x, y)
+					if color, ok := key_color(tile); (ok && !current.keys.Has(color)) || !passable(tile, current.keys) || is_hazard(tile) {
+						continue
+					}
+
+					for direction := up; direction <= right; direction++ {
+						next, ok := grid.step(current.pos, direction, current.keys)
+						if ok && !is_hazard(grid.Tile(next.X, next.Y)) {
+							next_state := state{next, current.keys.pick(grid.Tile(next.X, next.Y))}
+							previous[next_state] = append(previous[next_state], current)
+						}
+					}
+				}
+			}
+		}
+
+		var queue []state
+		for _, pos := range grid.exits() {
+			for keys := range grid.distances {
+				grid.distances[keys][pos.Y][pos.X] = 0
+				queue = append(queue, state{pos, Keys(keys)})
+			}
+		}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, before := range previous[current] {
+				if grid.distances[before.keys][before.pos.Y][before.pos.X] == -1 {
+					grid.distances[before.keys][before.pos.Y][before.pos.X] = grid.distances[current.keys][current.pos.Y][current.pos.X] + 1
+					queue = append(queue, before)
+				}
+			}
+		}
+	})
+
+	return grid.distances
+}
+
+// Number of steps from the position, holding the keys, to the nearest exit
+// -1 if there isn't a route
+func (grid *Grid) Distance(x int, y int, keys Keys) int {
+	if x < 0 || x >= grid.Width() || y < 0 || y >= grid.Height() {
+		return -1
+	}
+	distances := grid.exit_distances()
+	return distances[int(keys)&(len(distances)-1)][y][x]
+}
+
+// Shortest route from the start to the nearest exit (Path[0] is the start),
+// following the cells that are one step closer to an exit each time and
+// picking up the keys on the way (after a teleporter the next position is its
+// partner)
+// Returns nil if there isn't a route
+func (grid *Grid) ShortestPath() []Position {
+	pos, keys := grid.Start, Keys(0)
+	distance := grid.Distance(pos.X, pos.Y, keys)
+	if distance < 0 {
+		return nil
+	}
+
+	path := []Position{pos}
+	for distance > 0 {
+		for direction := up; direction <= right; direction++ {
+			next, ok := grid.step(pos, direction, keys)
+			if ok && grid.Distance(next.X, next.Y, keys.pick(grid.Tile(next.X, next.Y))) == distance-1 {
+				pos, keys = next, keys.pick(grid.Tile(next.X, next.Y))
+				break
+			}
+		}
+		distance--
+		path = append(path, pos)
+	}
+	return path
+}
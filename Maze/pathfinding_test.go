@@ -0,0 +1,96 @@
+package Maze
+
+import (
+	"strings"
+	"testing"
+)
+
+// Shortest routes of small maps, the route (ShortestPath) needs to be made of
+// moves of the players (step) and as long as the best solution (exit_distances)
+
+func TestShortestPath(t *testing.T) {
+	tests := []struct {
+		name string
+		maze string
+		best int
+	}{
+		{"corridor", `
+			11111
+			S...E
+			11111`, 4},
+		{"maze", `
+			1111111
+			S.1...1
+			1.1.1.1
+			1...1.E
+			1111111`, 12},
+		{"key behind a door", `
+			11111111111
+			1a1.......1
+			1.1.11111A1
+			S.....111.E
+			11111111111`, 18},
+		{"teleporter shortcut", `
+			1111111
+			S.w1w.E
+			1.111.1
+			1.....1
+			1111111`, 4},
+		{"one-way tile against the route", `
+			1111111
+			S..<..E
+			1.111.1
+			1.....1
+			1111111`, 10},
+		{"one-way tile on the route", `
+			1111111
+			S..>..E
+			1.111.1
+			1.....1
+			1111111`, 6},
+		{"hazard detour", `
+			1111111
+			S..~..E
+			1.111.1
+			1.....1
+			1111111`, 10},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grid, err := ParseMap(strings.NewReader(test.maze))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			path := grid.ShortestPath()
+			if grid.Best_solution != test.best {
+				t.Errorf("best solution %d, expected %d", grid.Best_solution, test.best)
+			}
+			if len(path)-1 != grid.Best_solution {
+				t.Fatalf("path of %d steps, the best solution is %d: %v", len(path)-1, grid.Best_solution, path)
+			}
+			if path[0] != grid.Start || !grid.is_exit(path[len(path)-1]) {
+				t.Errorf("the path doesn't go from the start to an exit: %v", path)
+			}
+
+			// Each position is reached by one move from the previous one
+			keys := Keys(0)
+			for i := 1; i < len(path); i++ {
+				legal := false
+				for direction := up; direction <= right; direction++ {
+					if next, ok := grid.step(path[i-1], direction, keys); ok && next == path[i] {
+						legal = true
+					}
+				}
+				if !legal {
+					t.Fatalf("step %d from %v to %v isn't a move of the players", i, path[i-1], path[i])
+				}
+				if is_hazard(grid.Tile(path[i].X, path[i].Y)) {
+					t.Fatalf("step %d enters the hazard on %v", i, path[i])
+				}
+				keys = keys.pick(grid.Tile(path[i].X, path[i].Y))
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package Maze
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"os"
+
+	"github.com/faiface/pixel"
+	"golang.org/x/image/colornames"
+	"golang.org/x/image/draw"
+)
+
+// ------------------------- Render to PNG ------------------------ //
+
+// Convert a sprite rect (pixel uses the bottom-left origin) to an image rect (top-left origin)
+func spriteToImageRect(r pixel.Rect, img_height int) image.Rectangle {
+	return image.Rect(int(r.Min.X), img_height-int(r.Max.Y), int(r.Max.X), img_height-int(r.Min.Y))
+}
+
+// Draw the selected map into a PNG file, without the need of an OpenGL context
+func RenderPNG(cfg Config, path string) error {
+
+	// Define the map
+	grid, err := LoadMap(cfg.Map)
+	if err != nil {
+		return err
+	}
+	backgroundMap := grid.Cells
+
+	// Load the spritemap image
+	file, err := os.Open("Images/spritemap-rpg.png")
+	if err != nil {
+		return fmt.Errorf("cannot read spritemap: %w", err)
+	}
+	defer file.Close()
+	spriteMap, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("cannot decode spritemap: %w", err)
+	}
+
+	// Reuse the same sprites of the PixelGL window
+	bgd := &background{}
+	bgd.setPlayerSprites()
+
+	// Same size and background color of the window
+	img := image.NewRGBA(image.Rect(0, 0, screen_width, screen_height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colornames.Lightgreen), image.Point{}, draw.Src)
+
+	for i := 0; i < len(backgroundMap); i++ { // Lines
+		for j := 0; j < len(backgroundMap[0]); j++ { // Columns
+			// Same position used on the window, converted to the top-left origin
+			pos := getObjectGridPosition(screen_width, screen_height, len(backgroundMap[0]), len(backgroundMap), j, (len(backgroundMap)-1)-i)
+			dst := spriteToImageRect(pos, screen_height)
+
+			// Keys (small squares), doors and hazards with their colors
+			if color, ok := key_color(backgroundMap[i][j]); ok {
+				draw.Draw(img, dst.Inset(dst.Dx()/3), image.NewUniform(key_palette[color]), image.Point{}, draw.Src)
+				continue
+			}
+			if color, ok := door_color(backgroundMap[i][j]); ok {
+				draw.Draw(img, dst.Inset(2), image.NewUniform(key_palette[color]), image.Point{}, draw.Src)
+				continue
+			}
+			if pair, ok := teleporter_pair(backgroundMap[i][j]); ok {
+				draw.Draw(img, dst.Inset(dst.Dx()/5), image.NewUniform(teleporter_palette[pair]), image.Point{}, draw.Src)
+				continue
+			}
+			if direction, ok := one_way_direction(backgroundMap[i][j]); ok {
+				draw.Draw(img, dst, image.NewUniform(colornames.Tan), image.Point{}, draw.Src)
+				draw_arrow(img, dst, direction)
+				continue
+			}
+			if is_hazard(backgroundMap[i][j]) {
+				draw.Draw(img, dst, image.NewUniform(hazard_palette[backgroundMap[i][j]-hazard_tile]), image.Point{}, draw.Src)
+				continue
+			}
+
+			// Path, don't draw anything
+			if backgroundMap[i][j] == 0 || bgd.sprites[int(backgroundMap[i][j])-1] == nil {
+				continue
+			}
+			src := spriteToImageRect(bgd.sprites[int(backgroundMap[i][j])-1][0], spriteMap.Bounds().Dy())
+
+			draw.ApproxBiLinear.Scale(img, dst, spriteMap, src, draw.Over, nil)
+		}
+	}
+
+	// Enemies on the start of their routes
+	for _, enemy := range grid.Enemies {
+		pos := getObjectGridPosition(screen_width, screen_height, grid.Width(), grid.Height(), enemy.At(0).X, enemy.At(0).Y)
+		src := spriteToImageRect(enemy_sprites[enemy.facing(0)], spriteMap.Bounds().Dy())
+		draw.ApproxBiLinear.Scale(img, spriteToImageRect(pos, screen_height), spriteMap, src, draw.Over, nil)
+	}
+
+	// Save the image
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("cannot create image: %w", err)
+	}
+	defer out.Close()
+
+	return png.Encode(out, img)
+}
+
+// Arrow of a one-way tile on the rectangle of the image
+func draw_arrow(img *image.RGBA, rect image.Rectangle, direction Direction) {
+	size := float64(rect.Dx())
+	center := pixel.V(float64(rect.Min.X+rect.Max.X)/2, float64(rect.Min.Y+rect.Max.Y)/2)
+
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			// Point on the arrow pointing up (the image Y grows down)
+			point := pixel.V(float64(x)-center.X, center.Y-float64(y)).Rotated(-one_way_angles[direction]).Scaled(1 / size)
+
+			head := point.Y >= 0.05 && point.Y <= 0.35 && math.Abs(point.X) <= (0.35-point.Y)
+			shaft := point.Y >= -0.35 && point.Y < 0.05 && math.Abs(point.X) <= 0.06
+			if head || shaft {
+				img.Set(x, y, colornames.Saddlebrown)
+			}
+		}
+	}
+}
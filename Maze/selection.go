@@ -0,0 +1,305 @@
+package Maze
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// ----------------------- Selection Strategies ------------------- //
+
+// Choose the parents of the next generation (one parent for each individual)
+// using the score of the individuals on the maze (bigger is better)
+// Returns the indexes of the parents on the population
+type Selection interface {
+	Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int
+}
+
+// Strategies with parameters check them when created by NewSelection
+type selection_validator interface {
+	validate() error
+}
+
+// Parameters of the built-in selection strategies
+type SelectionParams struct {
+	K                     int     // Tournament size
+	Rank_pressure         float64 // Linear rank: expected copies of the best individual (1.0 - 2.0)
+	Truncation_ratio      float64 // Truncation: fraction of the best individuals that can be parents
+	Boltzmann_temperature float64 // Boltzmann: initial temperature
+	Boltzmann_cooling     float64 // Boltzmann: temperature multiplier applied each generation
+}
+
+// Default parameters of the INI file
+var Default_selection_params = SelectionParams{
+	K:                     25,
+	Rank_pressure:         1.5,
+	Truncation_ratio:      0.5,
+	Boltzmann_temperature: 100,
+	Boltzmann_cooling:     0.95,
+}
+
+// Built-in selection strategies, selected by the INI file ([Settings] Selection)
+var selection_strategies = map[string]func(params SelectionParams) Selection{
+	"tournament": func(p SelectionParams) Selection { return TournamentSelection{K: p.K} },
+	"roulette":   func(p SelectionParams) Selection { return RouletteSelection{} },
+	"rank":       func(p SelectionParams) Selection { return RankSelection{Pressure: p.Rank_pressure} },
+	"sus":        func(p SelectionParams) Selection { return SUSSelection{} },
+	"truncation": func(p SelectionParams) Selection { return TruncationSelection{Ratio: p.Truncation_ratio} },
+	"boltzmann": func(p SelectionParams) Selection {
+		return BoltzmannSelection{Temperature: p.Boltzmann_temperature, Cooling: p.Boltzmann_cooling}
+	},
+}
+
+// Create a built-in selection strategy by its name
+func NewSelection(name string, params SelectionParams) (Selection, error) {
+	strategy, ok := selection_strategies[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("selection strategy '%s' not found (options: %s)", name, strings.Join(SelectionNames(), ", "))
+	}
+
+	// Just the parameters used by the strategy are checked
+	selection := strategy(params)
+	if validator, ok := selection.(selection_validator); ok {
+		if err := validator.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+// Names of the built-in selection strategies
+func SelectionNames() []string {
+	var names []string
+	for name := range selection_strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pick one index with probability proportional to its weight (uniform if all weights are 0)
+func weighted_index(rng *rand.Rand, weights []float64, total float64) int {
+	if total <= 0 {
+		return rng.Intn(len(weights))
+	}
+
+	target := rng.Float64() * total
+	for i := 0; i < len(weights); i++ {
+		target -= weights[i]
+		if target < 0 {
+			return i
+		}
+	}
+	return len(weights) - 1
+}
+
+// Scores shifted to start from 0, so negative scores (bump penalty) can be used as weights
+func score_weights(pop_score []int) ([]float64, float64) {
+	lowest := pop_score[0]
+	for _, score := range pop_score {
+		if score < lowest {
+			lowest = score
+		}
+	}
+
+	var total float64
+	weights := make([]float64, len(pop_score))
+	for i, score := range pop_score {
+		weights[i] = float64(score - lowest)
+		total += weights[i]
+	}
+	return weights, total
+}
+
+// Indexes of the individuals, from the best to the worst (ties keep the population order)
+func ranked_indexes(pop_score []int) []int {
+	indexes := make([]int, len(pop_score))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return pop_score[indexes[a]] > pop_score[indexes[b]]
+	})
+	return indexes
+}
+
+// ------------------------- Tournament --------------------------- //
+
+// K random competitors, the one with the best score wins
+type TournamentSelection struct {
+	K int
+}
+
+func (s TournamentSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	return define_parents(rng, pop, pop_score, len(pop), s.K)
+}
+
+func (s TournamentSelection) validate() error {
+	if s.K < 2 {
+		return fmt.Errorf("number of competitors (k) must be at least 2")
+	}
+	return nil
+}
+
+// -------------------------- Roulette ---------------------------- //
+
+// Chance of each individual proportional to its score
+type RouletteSelection struct{}
+
+func (RouletteSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	var parents []int
+
+	weights, total := score_weights(pop_score)
+	for i := 0; i < len(pop); i++ {
+		parents = append(parents, weighted_index(rng, weights, total))
+	}
+	return parents
+}
+
+// ------------------------ Linear Rank --------------------------- //
+
+// Chance of each individual proportional to its position on the ranking, so a
+// single individual with a huge score can't take over the population
+type RankSelection struct {
+	Pressure float64
+}
+
+func (s RankSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	var (
+		parents []int
+		total   float64
+	)
+
+	size := len(pop)
+	ranked := ranked_indexes(pop_score)
+
+	// The best one receives Pressure, the worst one 2 - Pressure
+	weights := make([]float64, size)
+	for position := 0; position < size; position++ {
+		weights[position] = 2 - s.Pressure
+		if size > 1 {
+			weights[position] += 2 * (s.Pressure - 1) * float64(size-1-position) / float64(size-1)
+		}
+		total += weights[position]
+	}
+
+	for i := 0; i < size; i++ {
+		parents = append(parents, ranked[weighted_index(rng, weights, total)])
+	}
+	return parents
+}
+
+func (s RankSelection) validate() error {
+	if s.Pressure < 1 || s.Pressure > 2 {
+		return fmt.Errorf("rank pressure should be between 1.0 and 2.0")
+	}
+	return nil
+}
+
+// ---------------- Stochastic Universal Sampling ----------------- //
+
+// Like the roulette, but with one spin and equally spaced pointers, so the
+// number of copies of each individual is close to the expected one
+type SUSSelection struct{}
+
+func (SUSSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	var parents []int
+
+	size := len(pop)
+	weights, total := score_weights(pop_score)
+
+	// All scores are the same, everybody is a parent
+	if total <= 0 {
+		for i := 0; i < size; i++ {
+			parents = append(parents, i)
+		}
+		return parents
+	}
+
+	spacing := total / float64(size)
+	pointer := rng.Float64() * spacing
+	sum := weights[0]
+	index := 0
+
+	for i := 0; i < size; i++ {
+		for sum <= pointer && index < size-1 {
+			index++
+			sum += weights[index]
+		}
+		parents = append(parents, index)
+		pointer += spacing
+	}
+	return parents
+}
+
+// -------------------------- Truncation -------------------------- //
+
+// Just the best individuals (Ratio of the population) can be parents, all
+// with the same chance
+type TruncationSelection struct {
+	Ratio float64
+}
+
+func (s TruncationSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	var parents []int
+
+	ranked := ranked_indexes(pop_score)
+	best := int(math.Ceil(float64(len(pop)) * s.Ratio))
+	if best < 1 {
+		best = 1
+	}
+
+	for i := 0; i < len(pop); i++ {
+		parents = append(parents, ranked[rng.Intn(best)])
+	}
+	return parents
+}
+
+func (s TruncationSelection) validate() error {
+	if s.Ratio <= 0 || s.Ratio > 1 {
+		return fmt.Errorf("truncation ratio should be between 0 and 1")
+	}
+	return nil
+}
+
+// -------------------------- Boltzmann --------------------------- //
+
+// Chance of each individual proportional to exp(score / temperature). The
+// temperature decreases each generation (Temperature * Cooling ^ generation),
+// so the selection starts almost random and gets more greedy with time
+type BoltzmannSelection struct {
+	Temperature float64
+	Cooling     float64
+}
+
+func (s BoltzmannSelection) Select(rng *rand.Rand, pop Population, pop_score []int, generation int) []int {
+	var (
+		parents []int
+		total   float64
+	)
+
+	temperature := math.Max(s.Temperature*math.Pow(s.Cooling, float64(generation)), 1e-6)
+
+	// Subtract the best score to avoid overflows on exp()
+	best := pop_score[ranked_indexes(pop_score)[0]]
+	weights := make([]float64, len(pop_score))
+	for i, score := range pop_score {
+		weights[i] = math.Exp(float64(score-best) / temperature)
+		total += weights[i]
+	}
+
+	for i := 0; i < len(pop); i++ {
+		parents = append(parents, weighted_index(rng, weights, total))
+	}
+	return parents
+}
+
+func (s BoltzmannSelection) validate() error {
+	if s.Temperature <= 0 || s.Cooling <= 0 {
+		return fmt.Errorf("boltzmann temperature and cooling should be positive")
+	}
+	return nil
+}
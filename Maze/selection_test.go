@@ -0,0 +1,34 @@
+package Maze
+
+import "testing"
+
+// Each strategy checks just its own parameters
+
+func TestNewSelection(t *testing.T) {
+	invalid := SelectionParams{K: 1, Rank_pressure: 3, Truncation_ratio: 0, Boltzmann_temperature: 0, Boltzmann_cooling: 0}
+
+	for _, name := range []string{"roulette", "sus"} {
+		if _, err := NewSelection(name, invalid); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		valid SelectionParams // Invalid parameters, but the ones of the strategy
+	}{
+		{"tournament", SelectionParams{K: 2, Rank_pressure: 3}},
+		{"rank", SelectionParams{K: 1, Rank_pressure: 1.5}},
+		{"truncation", SelectionParams{K: 1, Rank_pressure: 3, Truncation_ratio: 1}},
+		{"boltzmann", SelectionParams{K: 1, Rank_pressure: 3, Boltzmann_temperature: 10, Boltzmann_cooling: 0.9}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewSelection(test.name, invalid); err == nil {
+			t.Errorf("%s: the invalid parameters were accepted", test.name)
+		}
+		if _, err := NewSelection(test.name, test.valid); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package Maze
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+)
+
+// ------------------------ Configuration ------------------------- //
+
+// Simulation settings (the same values of the INI file)
+type Config struct {
+	Map                string           // Name of the map (maps directory) or file path // Default value = 1
+	Automation         bool             // Default value = true
+	Generations        int              // Default value = 100
+	Population_size    int              // Default value = 100
+	Gene_number        int              // Default value = 50
+	K                  int              // Tournament size (number of participants) // Default value = 25
+	Crossover_rate     float64          // Default value = 0.7
+	Mutation_rate      float64          // I'm analyzing each gene so the mutation rate should be really small // Default value = 0.05
+	Elitism_percentual int              // Default value = 10 (10% of population size)
+	Seed               int64            // Default value = 0 (random seed, chosen when the simulation starts)
+	Workers            int              // Goroutines that evaluate the population on Run and RunGeneration (0 = GOMAXPROCS)
+	Fitness            FitnessFunc      // Default value = column (nil = ColumnFitness)
+	Selection          Selection        // Default value = tournament (nil = TournamentSelection with K competitors)
+	Crossover          Crossover        // Default value = single (nil = single-point crossover)
+	Mutation_mix       MutationMix      // Default value = flip (nil = FlipMutation)
+	Adaptive_mutation  AdaptiveMutation // Default value = disabled (Stagnation = 0), Factor = 2, Max_rate = 0.3
+	Enemy_collision    string           // Players caught by an enemy die or go back to the start // Default value = kill (kill || restart)
+
+	// Console output of the generations summary and results (nil = os.Stdout)
+	Output io.Writer
+}
+
+// Individual that reached the objective
+type Result struct {
+	Generation int
+	Individual Genome
+	Exit       Position // Exit reached (the map can have more than one)
+	Steps      int
+}
+
+// Summary of the last generation processed by the genetic algorithm
+type Stats struct {
+	Generation          int
+	Mutated_individuals int
+	Mutated_genes       int     // Genes flipped
+	Mutated_commands    int     // Commands changed by the other operators of the mix
+	Mutation_rate       float64 // Mutation rate used on the generation (changed by the adaptive mutation)
+	Mutation_mix        string  // Percentage of each mutation operator
+	Crossovers          int
+	Best                Genome
+	Best_score          int
+	Average_score       int
+	Closest_distance    int // Steps from the closest position of the generation to an exit
+	Deaths              int // Individuals that entered a hazard or were caught by an enemy
+}
+
+// ------------------------- Simulation --------------------------- //
+
+// One independent run of the game (human) or of the genetic algorithm (automation)
+type Simulation struct {
+	Config     Config
+	Grid       *Grid
+	Population Population
+
+	// Random source used by all genetic algorithm steps
+	rng *rand.Rand
+
+	// Players (just player0 for humans, the whole population for automation)
+	players []*player
+
+	// Buffer where the children of the next generation are created
+	next_population Population
+
+	// Virtual keyboards that executes the commands of each individual
+	keyboard_automations map[Direction][]bool
+
+	// Counters
+	cycle               int
+	current_generation  int
+	elitism_individuals int
+
+	// Score
+	population_score     []int
+	population_traces    []Trace // Runs of the population, used by the crossover
+	closest_distance     int     // Closest distance to an exit on the generation (-1 before moving)
+	best_distance        int     // Closest distance to an exit of all generations
+	best_score           int     // Best score of all generations (adaptive mutation)
+	stagnant_generations int     // Generations without improving the best score
+
+	// Current mutation rate (changed by the adaptive mutation)
+	mutation_rate float64
+
+	// Objective slice
+	objective []Result
+
+	// Last generation summary
+	stats Stats
+
+	// Simulation finish and show results
+	finished bool
+}
+
+// Create a simulation: load the map and generate the population and the players
+func NewSimulation(cfg Config) (*Simulation, error) {
+
+	// Define the map
+	grid, err := LoadMap(cfg.Map)
+	if err != nil {
+		return nil, err
+	}
+
+	return new_simulation(cfg, grid)
+}
+
+// Create a simulation on a map already loaded
+func new_simulation(cfg Config, grid *Grid) (*Simulation, error) {
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+	if cfg.Fitness == nil {
+		cfg.Fitness = ColumnFitness{}
+	}
+	if cfg.Selection == nil {
+		cfg.Selection = TournamentSelection{K: cfg.K}
+	}
+	if cfg.Crossover == nil {
+		cfg.Crossover = PointCrossover{Points: 1}
+	}
+	if cfg.Mutation_mix == nil {
+		cfg.Mutation_mix = MutationMix{{Name: "flip", Operator: FlipMutation{}, Weight: 1}}
+	}
+	if cfg.Enemy_collision == "" {
+		cfg.Enemy_collision = Enemy_kills
+	}
+	if cfg.Enemy_collision != Enemy_kills && cfg.Enemy_collision != Enemy_restarts {
+		return nil, fmt.Errorf("enemy collision '%s' not found (options: %s, %s)", cfg.Enemy_collision, Enemy_kills, Enemy_restarts)
+	}
+	if cfg.Adaptive_mutation.Factor == 0 {
+		cfg.Adaptive_mutation.Factor = 2
+	}
+	if cfg.Adaptive_mutation.Max_rate == 0 {
+		cfg.Adaptive_mutation.Max_rate = 0.3
+	}
+	if cfg.Adaptive_mutation.Stagnation > 0 && (cfg.Adaptive_mutation.Factor <= 1 || cfg.Adaptive_mutation.Max_rate < cfg.Mutation_rate) {
+		return nil, fmt.Errorf("adaptive mutation factor should be bigger than 1 and the max rate bigger than the mutation rate")
+	}
+
+	sim := &Simulation{Config: cfg, Grid: grid, mutation_rate: cfg.Mutation_rate, closest_distance: -1, best_distance: -1}
+
+	// Seed of the random map, so it can be played again
+	if grid.Map_seed != 0 {
+		fmt.Fprintf(sim.Config.Output, "Map seed: %d\n", grid.Map_seed)
+	}
+
+	// Human mode, just player0
+	if !cfg.Automation {
+		sim.players = append(sim.players, &player{})
+		sim.players[0].restart_player(sim.players[0], sim.Grid.Start)
+		return sim, nil
+	}
+
+	// Validate parameters (K is just used by the tournament)
+	competitors := 2
+	if tournament, ok := cfg.Selection.(TournamentSelection); ok {
+		competitors = tournament.K
+	}
+	if err := validate_parameters(cfg.Population_size, competitors, cfg.Gene_number, cfg.Elitism_percentual); err != nil {
+		return nil, err
+	}
+	sim.elitism_individuals = (cfg.Elitism_percentual * cfg.Population_size) / 100
+
+	// Initialize the random source (the seed is kept to replay the same evolution)
+	sim.rng, sim.Config.Seed = new_random_source(cfg.Seed)
+	fmt.Fprintf(sim.Config.Output, "Seed: %d\n", sim.Config.Seed)
+
+	// 0 - Generate the population
+	// Generate each individual for population
+	// Each pair of genes is one command
+	sim.Population = new_population(cfg.Population_size, cfg.Gene_number/2)
+	sim.next_population = new_population(cfg.Population_size, cfg.Gene_number/2)
+	for i := 0; i < cfg.Population_size; i++ {
+		generate_individuals(sim.rng, sim.Population[i])
+	}
+
+	// Keyboard used by automations
+	sim.keyboard_automations = make(map[Direction][]bool)
+	for i := 0; i < len(sim.Population); i++ {
+		sim.keyboard_automations[up] = append(sim.keyboard_automations[up], false)
+		sim.keyboard_automations[down] = append(sim.keyboard_automations[down], false)
+		sim.keyboard_automations[left] = append(sim.keyboard_automations[left], false)
+		sim.keyboard_automations[right] = append(sim.keyboard_automations[right], false)
+	}
+
+	// Add players accordingly to population
+	for i := 0; i < cfg.Population_size; i++ {
+		sim.players = append(sim.players, &player{})
+		sim.players[i].restart_player(sim.players[i], sim.Grid.Start)
+	}
+
+	return sim, nil
+}
+
+// -------------------------- Automation -------------------------- //
+
+// Execute one cycle of the automation: press the virtual keyboards with the
+// next command of each individual or, when all the commands were executed,
+// run the genetic algorithm and start the next generation
+func (sim *Simulation) Step() {
+	if sim.finished || !sim.Config.Automation {
+		return
+	}
+
+	sim.automation_cycle()
+	sim.move_automated_players()
+}
+
+// Execute the current generation, evaluating the individuals on parallel
+// (the results are the same of executing it cycle by cycle with Step)
+func (sim *Simulation) RunGeneration() {
+	if sim.finished || !sim.Config.Automation {
+		return
+	}
+
+	// A generation started by Step is finished the same way
+	if sim.cycle > 0 {
+		generation := sim.current_generation
+		for !sim.finished && sim.current_generation == generation {
+			sim.Step()
+		}
+		return
+	}
+
+	sim.evaluate_parallel()
+
+	// If there are more generations to run
+	if sim.current_generation < sim.Config.Generations {
+		sim.end_generation()
+	} else {
+		sim.PrintResults()
+
+		// Show Results
+		sim.finished = true
+	}
+}
+
+// Execute all generations
+func (sim *Simulation) Run() {
+	for !sim.finished && sim.Config.Automation {
+		sim.RunGeneration()
+	}
+}
+
+// Check if all generations were executed
+func (sim *Simulation) Finished() bool {
+	return sim.finished
+}
+
+// Number of the current generation (starting from 0)
+func (sim *Simulation) Generation() int {
+	return sim.current_generation
+}
+
+// Summary of the last generation processed
+func (sim *Simulation) Stats() Stats {
+	return sim.stats
+}
+
+// All individuals that reached the objective
+func (sim *Simulation) Results() []Result {
+	return sim.objective
+}
+
+// Winner with less steps (the first one to reach the objective on ties)
+func (sim *Simulation) Best() (Result, bool) {
+	if len(sim.objective) == 0 {
+		return Result{}, false
+	}
+
+	best := 0
+	for i := 1; i < len(sim.objective); i++ {
+		if sim.objective[i].Steps < sim.objective[best].Steps {
+			best = i
+		}
+	}
+	return sim.objective[best], true
+}
+
+func (sim *Simulation) automation_cycle() {
+
+	// Number of commands of each individual
+	if sim.cycle == 0 {
+		for i := 0; i < len(sim.Population); i++ {
+			sim.players[i].trace.Commands = len(sim.Population[i])
+		}
+	}
+
+	// Loop for all commands available
+	if sim.cycle < len(sim.Population[0]) {
+
+		// Fill the commands in all virtual keyboards
+		for i := 0; i < len(sim.Population); i++ {
+			// Execute the command on keyboard
+
+			// UP[0] first player, UP[1] second player...
+			sim.keyboard_automations[sim.Population[i][sim.cycle]][i] = true
+		}
+
+		// Update cycle
+		sim.cycle++
+
+		// Finished all commands for this generation, reset and start again
+	} else {
+
+		// If there are more generations to run
+		if sim.current_generation < sim.Config.Generations {
+			sim.end_generation()
+		} else {
+			sim.PrintResults()
+
+			// Show Results
+			sim.finished = true
+		}
+	}
+}
+
+// Evaluate the population (when it wasn't evaluated by the workers), run the
+// genetic algorithm and restart the players for the next generation
+func (sim *Simulation) end_generation() {
+
+	// Update the Score slice
+	if sim.population_score == nil {
+		for i := 0; i < sim.Config.Population_size; i++ {
+			sim.population_score = append(sim.population_score, sim.player_score(i))
+		}
+	}
+	for i := 0; i < sim.Config.Population_size; i++ {
+		sim.population_traces = append(sim.population_traces, sim.players[i].trace)
+	}
+
+	// Clean variables for the next generation
+	sim.cycle = 0
+	// // Restart game for next individual
+	for i := 0; i < sim.Config.Population_size; i++ {
+		sim.players[i].restart_player(sim.players[i], sim.Grid.Start)
+	}
+
+	sim.genetic_algorithm()
+	sim.current_generation++
+	sim.closest_distance = -1
+}
+
+// Number of players dead on the current generation
+func (sim *Simulation) deaths() int {
+	deaths := 0
+	for _, object := range sim.players {
+		if object.trace.Death_step > 0 {
+			deaths++
+		}
+	}
+	return deaths
+}
+
+// Keep the closest distance to an exit reached on the generation
+func (sim *Simulation) record_distance(pos Position, keys Keys) {
+	distance := sim.Grid.Distance(pos.X, pos.Y, keys)
+	if distance >= 0 && (sim.closest_distance < 0 || distance < sim.closest_distance) {
+		sim.closest_distance = distance
+	}
+}
+
+// Move the automated players accordingly to its virtual keyboards and release the keys
+func (sim *Simulation) move_automated_players() {
+	for i := 0; i < len(sim.Population); i++ {
+		if sim.keyboard_automations[up][i] == true {
+			sim.players[i].update(sim, up, i)
+		}
+		if sim.keyboard_automations[down][i] == true {
+			sim.players[i].update(sim, down, i)
+		}
+		if sim.keyboard_automations[left][i] == true {
+			sim.players[i].update(sim, left, i)
+		}
+		if sim.keyboard_automations[right][i] == true {
+			sim.players[i].update(sim, right, i)
+		}
+	}
+
+	// Clean key pressed for the next cycle
+	for i := 0; i < len(sim.Population); i++ {
+		sim.keyboard_automations[up][i] = false
+		sim.keyboard_automations[down][i] = false
+		sim.keyboard_automations[left][i] = false
+		sim.keyboard_automations[right][i] = false
+	}
+}
+
+// Print the winners of the simulation to the console
+func (sim *Simulation) PrintResults() {
+	out := sim.Config.Output
+
+	fmt.Fprintf(out, "\n\n\n|| ---------------------------------- Simulation Ended ---------------------------------- ||\n\nSeed: %d\n\nWinners:\n", sim.Config.Seed)
+	for i := 0; i < len(sim.objective); i++ {
+		fmt.Fprintf(out, "%d\tGen: %d\tIndividual: %s\tExit: %d,%d\tSteps: %d\n", i+1, sim.objective[i].Generation, sim.objective[i].Individual, sim.objective[i].Exit.X, sim.objective[i].Exit.Y, sim.objective[i].Steps)
+	}
+
+	// Calculate the best one (less steps)
+	quickest := sim.Config.Gene_number / 2
+	for i := 0; i < len(sim.objective); i++ {
+		if sim.objective[i].Steps < quickest {
+			quickest = sim.objective[i].Steps
+		}
+	}
+
+	fmt.Fprintf(out, "\nBest performances:\n")
+	for i := 0; i < len(sim.objective); i++ {
+		if sim.objective[i].Steps == quickest {
+			fmt.Fprintf(out, "Gen: %d\tIndividual: %s\tExit: %d,%d\tSteps: %d\n", sim.objective[i].Generation, sim.objective[i].Individual, sim.objective[i].Exit.X, sim.objective[i].Exit.Y, sim.objective[i].Steps)
+		}
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,119 @@
+package Maze
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// The same seed replays the same evolution, whatever the number of workers
+// that evaluate the population and with the cycle by cycle execution (Step)
+
+func replay_config(maze_map string, workers int, output *bytes.Buffer) Config {
+	return Config{
+		Map:                maze_map,
+		Automation:         true,
+		Generations:        8,
+		Population_size:    60,
+		Gene_number:        60,
+		K:                  5,
+		Crossover_rate:     0.7,
+		Mutation_rate:      0.05,
+		Elitism_percentual: 10,
+		Seed:               42,
+		Workers:            workers,
+		Fitness:            DistanceFitness{},
+		Output:             output,
+	}
+}
+
+// Output and best individual of a simulation, executed by RunGeneration or Step
+func replay(t *testing.T, cfg Config, step bool) (string, string) {
+	sim, err := NewSimulation(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for !sim.Finished() {
+		if step {
+			sim.Step()
+		} else {
+			sim.RunGeneration()
+		}
+	}
+
+	best := "none"
+	if result, ok := sim.Best(); ok {
+		best = fmt.Sprintf("%s (%d steps, generation %d)", result.Individual, result.Steps, result.Generation)
+	}
+	return cfg.Output.(*bytes.Buffer).String(), best
+}
+
+func TestSeedReplay(t *testing.T) {
+	for _, name := range []string{"0", "1", "2", "3"} {
+		t.Run("map "+name, func(t *testing.T) {
+			maze_map := "../maps/" + name + ".map"
+
+			output, best := replay(t, replay_config(maze_map, 1, &bytes.Buffer{}), false)
+			if !strings.Contains(output, "GENERATION: 7") {
+				t.Fatalf("the generations weren't printed:\n%s", output)
+			}
+
+			runs := map[string]Config{
+				"GOMAXPROCS workers": replay_config(maze_map, runtime.GOMAXPROCS(0), &bytes.Buffer{}),
+				"3 workers":          replay_config(maze_map, 3, &bytes.Buffer{}),
+			}
+			for run, cfg := range runs {
+				if run_output, run_best := replay(t, cfg, false); run_output != output || run_best != best {
+					t.Errorf("%s: different evolution than 1 worker\nbest: %s, 1 worker: %s", run, run_best, best)
+				}
+			}
+
+			if step_output, step_best := replay(t, replay_config(maze_map, 1, &bytes.Buffer{}), true); step_output != output || step_best != best {
+				t.Errorf("Step: different evolution than RunGeneration\nbest: %s, RunGeneration: %s", step_best, best)
+			}
+		})
+	}
+}
+
+// Invalid parameters are errors of NewSimulation (the genetic algorithm doesn't start)
+func TestNewSimulationParameters(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		err    string // Part of the error message
+	}{
+		{"odd population", func(cfg *Config) { cfg.Population_size = 61 }, "EVEN"},
+		{"no population", func(cfg *Config) { cfg.Population_size = 0 }, "positive"},
+		{"one gene", func(cfg *Config) { cfg.Gene_number = 1 }, "gene number"},
+		{"one competitor", func(cfg *Config) { cfg.K = 1 }, "competitors"},
+		{"elitism above 100%", func(cfg *Config) { cfg.Elitism_percentual = 150 }, "elitism"},
+		{"negative elitism", func(cfg *Config) { cfg.Elitism_percentual = -10 }, "elitism"},
+		{"unknown enemy collision", func(cfg *Config) { cfg.Enemy_collision = "ignore" }, "enemy collision"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := replay_config("../maps/0.map", 1, &bytes.Buffer{})
+			test.change(&cfg)
+			_, err := NewSimulation(cfg)
+			if err == nil {
+				t.Fatal("the invalid parameters were accepted")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("error '%s', expected '%s'", err, test.err)
+			}
+		})
+	}
+
+	// The limits of the elitism
+	for _, elitism := range []int{0, 100} {
+		cfg := replay_config("../maps/0.map", 1, &bytes.Buffer{})
+		cfg.Elitism_percentual = elitism
+		if _, err := NewSimulation(cfg); err != nil {
+			t.Errorf("elitism %d%%: %s", elitism, err)
+		}
+	}
+}
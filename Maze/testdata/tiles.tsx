@@ -0,0 +1,24 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="tiles" tilewidth="32" tileheight="32" tilecount="4" columns="4">
+ <image source="tiles.png" width="128" height="32"/>
+ <tile id="0">
+  <properties>
+   <property name="tile" value="1"/>
+  </properties>
+ </tile>
+ <tile id="1">
+  <properties>
+   <property name="tile" value="2"/>
+  </properties>
+ </tile>
+ <tile id="2">
+  <properties>
+   <property name="tile" value="~"/>
+  </properties>
+ </tile>
+ <tile id="3">
+  <properties>
+   <property name="tile" value="."/>
+  </properties>
+ </tile>
+</tileset>
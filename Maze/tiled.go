@@ -0,0 +1,534 @@
+package Maze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ---------------------- Tiled Maps (TMX / TMJ) ------------------ //
+
+// The maps of the Tiled editor (https://www.mapeditor.org) are read with the
+// same structs for both formats, the XML (.tmx / .tsx) and the JSON (.tmj / .tsj)
+// have almost the same names
+
+// Flags of flipped and rotated tiles on the high bits of the gids
+const tiled_flags = 0xF0000000
+
+// Value of a property (any JSON type is read as text)
+type tiled_value string
+
+func (value *tiled_value) UnmarshalJSON(data []byte) error {
+	*value = tiled_value(strings.Trim(string(data), `"`))
+	return nil
+}
+
+type tiled_property struct {
+	Name  string      `xml:"name,attr" json:"name"`
+	Value tiled_value `xml:"value,attr" json:"value"`
+}
+
+// The XML has the image as an element, the JSON as fields of the tileset or tile
+type tiled_image struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+type tiled_tile struct {
+	Id         int              `xml:"id,attr" json:"id"`
+	Properties []tiled_property `xml:"properties>property" json:"properties"`
+
+	// Image of the tiles of collection tilesets, X, Y, Width and Height are
+	// the part of the image used (the whole image when not set)
+	Xml_image    tiled_image `xml:"image" json:"-"`
+	Image        string      `xml:"-" json:"image"`
+	Image_width  int         `xml:"-" json:"imagewidth"`
+	Image_height int         `xml:"-" json:"imageheight"`
+	X            int         `xml:"x,attr" json:"x"`
+	Y            int         `xml:"y,attr" json:"y"`
+	Width        int         `xml:"width,attr" json:"width"`
+	Height       int         `xml:"height,attr" json:"height"`
+}
+
+type tiled_tileset struct {
+	First_gid uint32 `xml:"firstgid,attr" json:"firstgid"`
+	Source    string `xml:"source,attr" json:"source"` // External tileset (.tsx or .tsj)
+
+	Name         string       `xml:"name,attr" json:"name"`
+	Tile_width   int          `xml:"tilewidth,attr" json:"tilewidth"`
+	Tile_height  int          `xml:"tileheight,attr" json:"tileheight"`
+	Tile_count   int          `xml:"tilecount,attr" json:"tilecount"`
+	Columns      int          `xml:"columns,attr" json:"columns"`
+	Margin       int          `xml:"margin,attr" json:"margin"`
+	Spacing      int          `xml:"spacing,attr" json:"spacing"`
+	Xml_image    tiled_image  `xml:"image" json:"-"`
+	Image        string       `xml:"-" json:"image"`
+	Image_width  int          `xml:"-" json:"imagewidth"`
+	Image_height int          `xml:"-" json:"imageheight"`
+	Tiles        []tiled_tile `xml:"tile" json:"tiles"`
+}
+
+// Gids of a tile layer: CSV or base64 (optionally gzip or zlib) text, the
+// tile elements of the XML or the array of the JSON
+type tiled_data struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Text        string `xml:",chardata"`
+	Tiles       []struct {
+		Gid uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+
+	gids []uint32
+}
+
+func (data *tiled_data) UnmarshalJSON(text []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(text), []byte(`"`)) {
+		return json.Unmarshal(text, &data.Text)
+	}
+	return json.Unmarshal(text, &data.gids)
+}
+
+type tiled_object struct {
+	Name   string  `xml:"name,attr" json:"name"`
+	Type   string  `xml:"type,attr" json:"type"`
+	Class  string  `xml:"class,attr" json:"class"` // Type of Tiled 1.9
+	Gid    uint32  `xml:"gid,attr" json:"gid"`
+	X      float64 `xml:"x,attr" json:"x"`
+	Y      float64 `xml:"y,attr" json:"y"`
+	Width  float64 `xml:"width,attr" json:"width"`
+	Height float64 `xml:"height,attr" json:"height"`
+
+	// Route of the enemies, relative to the object (the XML has the points on a text)
+	Xml_polyline struct {
+		Points string `xml:"points,attr"`
+	} `xml:"polyline" json:"-"`
+	Polyline []tiled_point `xml:"-" json:"polyline"`
+}
+
+type tiled_point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Points of the polyline of the object, with the coordinates of the map
+func (object tiled_object) polyline() ([]tiled_point, error) {
+	points := object.Polyline
+	if object.Xml_polyline.Points != "" {
+		points = nil
+		for _, field := range strings.Fields(object.Xml_polyline.Points) {
+			x_text, y_text, _ := strings.Cut(field, ",")
+			x, err_x := strconv.ParseFloat(x_text, 64)
+			y, err_y := strconv.ParseFloat(y_text, 64)
+			if err_x != nil || err_y != nil {
+				return nil, fmt.Errorf("invalid point '%s' of the polyline", field)
+			}
+			points = append(points, tiled_point{x, y})
+		}
+	}
+
+	for i := range points {
+		points[i].X += object.X
+		points[i].Y += object.Y
+	}
+	return points, nil
+}
+
+// Tile layer or object layer (the XML has them on different elements, the
+// JSON on the same list with their type)
+type tiled_layer struct {
+	Name        string         `xml:"name,attr" json:"name"`
+	Type        string         `xml:"-" json:"type"`
+	Width       int            `xml:"width,attr" json:"width"`
+	Height      int            `xml:"height,attr" json:"height"`
+	Encoding    string         `xml:"-" json:"encoding"`
+	Compression string         `xml:"-" json:"compression"`
+	Data        tiled_data     `xml:"data" json:"data"`
+	Objects     []tiled_object `xml:"object" json:"objects"`
+}
+
+type tiled_map struct {
+	Orientation   string          `xml:"orientation,attr" json:"orientation"`
+	Infinite      bool            `xml:"infinite,attr" json:"infinite"`
+	Width         int             `xml:"width,attr" json:"width"`
+	Height        int             `xml:"height,attr" json:"height"`
+	Tile_width    int             `xml:"tilewidth,attr" json:"tilewidth"`
+	Tile_height   int             `xml:"tileheight,attr" json:"tileheight"`
+	Tilesets      []tiled_tileset `xml:"tileset" json:"tilesets"`
+	Layers        []tiled_layer   `xml:"layer" json:"layers"`
+	Object_groups []tiled_layer   `xml:"objectgroup" json:"-"`
+	Groups        []tiled_layer   `xml:"group" json:"-"`
+}
+
+// Tiled map file
+func tiled_map_file(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	return extension == ".tmx" || extension == ".tmj"
+}
+
+// Read a XML (.tmx, .tsx) or JSON file of Tiled
+func read_tiled_file(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmx", ".tsx":
+		err = xml.Unmarshal(data, value)
+	default:
+		err = json.Unmarshal(data, value)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// Import a map of Tiled (.tmx or .tmj). The tile layers have the tiles of
+// the grid, each tile of the tilesets is converted by its "tile" property
+// (0 path, 1 - 4 trees, a - d keys, A - D doors, ~ % ^ hazards, w - z
+// teleporters, n v < > one-way tiles) or, when the tileset uses Images/spritemap-rpg.png,
+// by the sprite of the game on it. Empty tiles are path. The objects of the
+// object layers are the markers, by their type (or class, or name): "start"
+// (exactly one) and "exit" (at least one), on the tile of their center, and
+// "enemy", walking through the tiles of the points of its polyline (or
+// standing on the tile of a point).
+// The map is checked like the map files, the problems are MapErrors with the
+// lines and columns of the tiles (from the top)
+func ImportTiled(path string) (*Grid, error) {
+	var tmap tiled_map
+	if err := read_tiled_file(path, &tmap); err != nil {
+		return nil, err
+	}
+
+	var layers []tiled_layer
+	if strings.ToLower(filepath.Ext(path)) == ".tmx" {
+		for i := range tmap.Layers {
+			tmap.Layers[i].Type = "tilelayer"
+		}
+		for _, layer := range tmap.Object_groups {
+			layer.Type = "objectgroup"
+			layers = append(layers, layer)
+		}
+		for _, layer := range tmap.Groups {
+			layer.Type = "group"
+			layers = append(layers, layer)
+		}
+	}
+	layers = append(tmap.Layers, layers...)
+
+	if tmap.Infinite {
+		return nil, MapErrors{{Message: "infinite maps aren't supported, the map needs a fixed size"}}
+	}
+	if tmap.Orientation != "" && tmap.Orientation != "orthogonal" {
+		return nil, MapErrors{{Message: fmt.Sprintf("%s maps aren't supported, just orthogonal", tmap.Orientation)}}
+	}
+	if tmap.Width < 1 || tmap.Height < 1 || tmap.Tile_width < 1 || tmap.Tile_height < 1 {
+		return nil, MapErrors{{Message: "the map needs a width, a height and the size of the tiles"}}
+	}
+
+	// External tilesets, relative to the map
+	for i := range tmap.Tilesets {
+		tileset := &tmap.Tilesets[i]
+		if tileset.Source != "" {
+			first_gid := tileset.First_gid
+			if err := read_tiled_file(filepath.Join(filepath.Dir(path), tileset.Source), tileset); err != nil {
+				return nil, fmt.Errorf("tileset: %w", err)
+			}
+			tileset.First_gid = first_gid
+		}
+	}
+
+	var (
+		issues MapErrors
+		start  []Position
+		exits  []Position
+
+		// Waypoints of the enemies and the names of their layers
+		enemies      [][]Position
+		enemy_layers []string
+	)
+
+	// Tiles, the tiles of the next layers are drawn over the previous ones
+	cells := make([][]uint8, tmap.Height)
+	for line := range cells {
+		cells[line] = make([]uint8, tmap.Width)
+	}
+	reported := make(map[uint32]bool)
+	for _, layer := range layers {
+		switch layer.Type {
+		case "objectgroup", "imagelayer":
+			continue // Markers and pictures
+		case "tilelayer":
+		default:
+			issues = append(issues, MapIssue{Message: fmt.Sprintf("layer '%s': %s layers aren't supported", layer.Name, layer.Type)})
+			continue
+		}
+
+		if layer.Encoding != "" {
+			layer.Data.Encoding, layer.Data.Compression = layer.Encoding, layer.Compression
+		}
+		gids, err := layer.Data.decode()
+		if err != nil {
+			issues = append(issues, MapIssue{Message: fmt.Sprintf("layer '%s': %s", layer.Name, err)})
+			continue
+		}
+		if len(gids) != tmap.Width*tmap.Height {
+			issues = append(issues, MapIssue{Message: fmt.Sprintf("layer '%s': %d tiles, the map has %d x %d", layer.Name, len(gids), tmap.Width, tmap.Height)})
+			continue
+		}
+
+		for i, gid := range gids {
+			gid &^= tiled_flags
+			if gid == 0 {
+				continue // Empty, the tile below stays
+			}
+
+			line, column := i/tmap.Width, i%tmap.Width
+			tile, err := tmap.tile(gid)
+			if err != nil {
+				if !reported[gid] {
+					issues = append(issues, MapIssue{line + 1, column + 1, err.Error()})
+					reported[gid] = true
+				}
+				continue
+			}
+			cells[line][column] = tile
+		}
+	}
+
+	// Markers
+	for _, layer := range layers {
+		if layer.Type != "objectgroup" {
+			continue
+		}
+
+		for _, object := range layer.Objects {
+			kind := object.Type
+			if kind == "" {
+				kind = object.Class
+			}
+			if kind == "" {
+				kind = object.Name
+			}
+
+			// Center of the object, the tile objects have the position on their bottom
+			x, y := object.X+object.Width/2, object.Y+object.Height/2
+			if object.Gid != 0 {
+				y = object.Y - object.Height/2
+			}
+			pos := Position{int(math.Floor(x / float64(tmap.Tile_width))), int(math.Floor(y / float64(tmap.Tile_height)))}
+			if pos.X < 0 || pos.X >= tmap.Width || pos.Y < 0 || pos.Y >= tmap.Height {
+				issues = append(issues, MapIssue{Message: fmt.Sprintf("layer '%s': the marker '%s' is outside the map", layer.Name, kind)})
+				continue
+			}
+
+			switch strings.ToLower(kind) {
+			case "start":
+				if len(start) > 0 {
+					issues = append(issues, MapIssue{pos.Y + 1, pos.X + 1, "another start, the map needs just one"})
+				}
+				start = append(start, pos)
+			case "exit":
+				exits = append(exits, pos)
+			case "enemy":
+				points, err := object.polyline()
+				if err != nil {
+					issues = append(issues, MapIssue{pos.Y + 1, pos.X + 1, fmt.Sprintf("enemy: %s", err)})
+					continue
+				}
+				waypoints := []Position{pos}
+				if len(points) > 0 {
+					waypoints = nil
+					for _, point := range points {
+						waypoints = append(waypoints, Position{int(math.Floor(point.X / float64(tmap.Tile_width))), int(math.Floor(point.Y / float64(tmap.Tile_height)))})
+					}
+				}
+				enemies = append(enemies, waypoints)
+				enemy_layers = append(enemy_layers, layer.Name)
+			default:
+				issues = append(issues, MapIssue{pos.Y + 1, pos.X + 1, fmt.Sprintf("unknown marker '%s' (start, exit or enemy)", kind)})
+			}
+		}
+	}
+
+	if len(start) == 0 {
+		issues = append(issues, MapIssue{Message: "the map needs one start marker"})
+	}
+	if len(exits) == 0 {
+		issues = append(issues, MapIssue{Message: "the map needs at least one exit marker"})
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	grid, err := new_grid(cells, start[0], exits)
+	if err != nil {
+		return nil, err
+	}
+	for i, waypoints := range enemies {
+		if err := grid.add_enemy(waypoints); err != nil {
+			issues = append(issues, MapIssue{Message: fmt.Sprintf("layer '%s': %s", enemy_layers[i], err)})
+		}
+	}
+	if len(issues) > 0 {
+		return nil, issues
+	}
+	return grid, nil
+}
+
+// Gids of the layer data
+func (data tiled_data) decode() ([]uint32, error) {
+	switch data.Encoding {
+	case "":
+		if data.gids != nil {
+			return data.gids, nil
+		}
+		gids := make([]uint32, len(data.Tiles))
+		for i, tile := range data.Tiles {
+			gids[i] = tile.Gid
+		}
+		return gids, nil
+
+	case "csv":
+		var gids []uint32
+		for _, field := range strings.Split(data.Text, ",") {
+			gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tile '%s'", strings.TrimSpace(field))
+			}
+			gids = append(gids, uint32(gid))
+		}
+		return gids, nil
+
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(data.Text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data")
+		}
+
+		var reader io.Reader = bytes.NewReader(raw)
+		switch data.Compression {
+		case "":
+		case "gzip":
+			reader, err = gzip.NewReader(reader)
+		case "zlib":
+			reader, err = zlib.NewReader(reader)
+		default:
+			return nil, fmt.Errorf("%s compression isn't supported (use gzip, zlib or none)", data.Compression)
+		}
+		if err == nil {
+			raw, err = io.ReadAll(reader)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s data", data.Compression)
+		}
+
+		gids := make([]uint32, len(raw)/4)
+		for i := range gids {
+			gids[i] = binary.LittleEndian.Uint32(raw[4*i:])
+		}
+		return gids, nil
+	}
+
+	return nil, fmt.Errorf("%s encoding isn't supported", data.Encoding)
+}
+
+// Tile of the grid of a gid
+func (tmap *tiled_map) tile(gid uint32) (uint8, error) {
+
+	// Tileset of the gid: the one with the highest first gid below it
+	var tileset *tiled_tileset
+	for i := range tmap.Tilesets {
+		if tmap.Tilesets[i].First_gid <= gid && (tileset == nil || tmap.Tilesets[i].First_gid > tileset.First_gid) {
+			tileset = &tmap.Tilesets[i]
+		}
+	}
+	if tileset == nil {
+		return 0, fmt.Errorf("tile %d isn't on any tileset", gid)
+	}
+
+	if tile, ok := tileset.tile(int(gid - tileset.First_gid)); ok {
+		return tile, nil
+	}
+	return 0, fmt.Errorf("tile %d of the tileset '%s' isn't a tile of the game (add the property tile = 0 - 4, a key a - d, a door A - D, a hazard ~ %% ^, a teleporter w - z or a one-way tile n v < >)", gid-tileset.First_gid, tileset.Name)
+}
+
+// Tile of the grid of a tile of the tileset, by its property or its sprite
+func (tileset *tiled_tileset) tile(id int) (uint8, bool) {
+	image_path, image_width, image_height := tileset.Image, tileset.Image_width, tileset.Image_height
+	if tileset.Xml_image.Source != "" {
+		image_path, image_width, image_height = tileset.Xml_image.Source, tileset.Xml_image.Width, tileset.Xml_image.Height
+	}
+
+	// Sprite of the tile on a image with the tiles side by side
+	columns := tileset.Columns
+	if columns == 0 && tileset.Tile_width > 0 {
+		columns = (image_width - 2*tileset.Margin + tileset.Spacing) / (tileset.Tile_width + tileset.Spacing)
+	}
+	var sprite image.Rectangle
+	if columns > 0 {
+		x := tileset.Margin + (id%columns)*(tileset.Tile_width+tileset.Spacing)
+		y := tileset.Margin + (id/columns)*(tileset.Tile_height+tileset.Spacing)
+		sprite = image.Rect(x, y, x+tileset.Tile_width, y+tileset.Tile_height)
+	}
+
+	for _, tile := range tileset.Tiles {
+		if tile.Id != id {
+			continue
+		}
+
+		// Same characters of the map files, besides the markers
+		for _, property := range tile.Properties {
+			if property.Name == "tile" {
+				value := []rune(string(property.Value))
+				if len(value) != 1 || value[0] == 'S' || value[0] == 'E' {
+					return 0, false
+				}
+				tile, ok := map_tiles[value[0]]
+				return tile, ok
+			}
+		}
+
+		// Collection of images
+		width, height := tile.Image_width, tile.Image_height
+		if tile.Xml_image.Source != "" {
+			image_path, width, height = tile.Xml_image.Source, tile.Xml_image.Width, tile.Xml_image.Height
+		} else if tile.Image != "" {
+			image_path = tile.Image
+		}
+		if tile.Xml_image.Source != "" || tile.Image != "" {
+			image_height = height
+			if tile.Width > 0 && tile.Height > 0 {
+				width, height = tile.Width, tile.Height
+			}
+			sprite = image.Rect(tile.X, tile.Y, tile.X+width, tile.Y+height)
+		}
+	}
+
+	// Same trees of the game
+	if filepath.Base(image_path) != "spritemap-rpg.png" {
+		return 0, false
+	}
+	bgd := &background{}
+	bgd.setPlayerSprites()
+	for tile := 1; tile <= 4; tile++ {
+		if sprite == spriteToImageRect(bgd.sprites[tile-1][0], image_height) {
+			return uint8(tile), true
+		}
+	}
+	return 0, false
+}
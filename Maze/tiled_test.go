@@ -0,0 +1,40 @@
+package Maze
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The same map of Tiled on every encoding of the layer data (testdata), with
+// the tiles converted by the properties of the tileset
+
+func TestImportTiled(t *testing.T) {
+	cells := [][]uint8{
+		{1, 1, 1, 1, 1, 1},
+		{0, 0, 0, 0, 2, 1},
+		{1, 0, hazard_tile, 0, 0, 0},
+		{1, 1, 1, 1, 1, 1},
+	}
+
+	for _, file := range []string{"csv.tmx", "base64.tmx", "gzip.tmx", "zlib.tmx", "xml.tmx", "array.tmj", "zlib.tmj"} {
+		t.Run(file, func(t *testing.T) {
+			grid, err := ImportTiled("testdata/" + file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(grid.Cells, cells) {
+				t.Errorf("cells %v, expected %v", grid.Cells, cells)
+			}
+			if grid.Start != (Position{0, 2}) {
+				t.Errorf("start %v, expected {0 2}", grid.Start)
+			}
+			if !reflect.DeepEqual(grid.Exits, []Position{{5, 1}}) {
+				t.Errorf("exits %v, expected [{5 1}]", grid.Exits)
+			}
+			if grid.Best_solution != 6 {
+				t.Errorf("best solution %d, expected 6", grid.Best_solution)
+			}
+		})
+	}
+}
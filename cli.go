@@ -0,0 +1,315 @@
+package main
+
+import (
+	"Maze_Game/Maze"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/faiface/pixel/pixelgl"
+)
+
+// Command line options, each one overrides the INI value with the same name
+type options struct {
+	maze_map           *string
+	automation         *bool
+	generations        *int
+	population_size    *int
+	gene_number        *int
+	k                  *int
+	crossover_rate     *float64
+	mutation_rate      *float64
+	elitism_percentual *int
+	seed               *int64
+	fitness            *string
+	selection          *string
+	rank_pressure      *float64
+	truncation_ratio   *float64
+	boltzmann_temp     *float64
+	boltzmann_cooling  *float64
+	crossover          *string
+	crossover_points   *int
+	uniform_rate       *float64
+	crossover_aligned  *bool
+	mutation_mix       *string
+	adaptive_stag      *int
+	adaptive_factor    *float64
+	adaptive_max_rate  *float64
+	enemy_collision    *string
+	headless           *bool
+	workers            *int
+	output             *string
+	size               *string
+	files              []string // Arguments after the flags (edit)
+}
+
+// Available subcommands and its descriptions
+var subcommands = []struct{ name, description string }{
+	{"play", "Play the maze with the keyboard"},
+	{"evolve", "Watch the genetic algorithm evolving on the window (or use --headless)"},
+	{"solve", "Run the genetic algorithm without a window and print the best route"},
+	{"render", "Draw the map into a PNG image"},
+	{"edit", "Edit a map file on the window: maze edit [--size 20x10] <file>"},
+	{"lint-map", "Check map files and report their problems: maze lint-map <file>..."},
+}
+
+// Split the subcommand from its arguments
+// Without a subcommand the mode is defined by the INI file ([Mode] Automation)
+func parse_subcommand(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", args
+	}
+
+	for _, cmd := range subcommands {
+		if args[0] == cmd.name {
+			return args[0], args[1:]
+		}
+	}
+
+	fmt.Printf("Unknown command: %s\n\n", args[0])
+	usage()
+	os.Exit(2)
+	return "", nil
+}
+
+// Define the flags accepted by the subcommand
+func define_flags(command string) (*flag.FlagSet, *options) {
+	fs := flag.NewFlagSet("maze "+command, flag.ExitOnError)
+	opts := &options{}
+
+	// Just the files to be checked
+	if command == "lint-map" {
+		fs.Usage = func() {
+			fmt.Printf("Usage: maze lint-map <file>...\n\nCheck the map files (or names of the maps directory) and report their problems.\n")
+		}
+		return fs, opts
+	}
+
+	// [Maps]
+	opts.maze_map = fs.String("map", "", "Map to be used: name of the maps directory or file path")
+
+	// [Mode] - Just without subcommand, otherwise the subcommand defines the mode
+	if command == "" {
+		opts.automation = fs.Bool("automation", false, "Run the genetic algorithm instead of the human mode")
+	}
+
+	// [Settings]
+	if command == "" || command == "evolve" || command == "solve" || command == "edit" {
+		opts.generations = fs.Int("generations", 0, "Number of generations")
+		opts.population_size = fs.Int("population-size", 0, "Population size")
+		opts.gene_number = fs.Int("gene-number", 0, "Number of genes of each individual")
+		opts.k = fs.Int("k", 0, "Number of participants of tournament for parents selection")
+		opts.crossover_rate = fs.Float64("crossover-rate", 0, "Crossover rate")
+		opts.mutation_rate = fs.Float64("mutation-rate", 0, "Mutation rate")
+		opts.elitism_percentual = fs.Int("elitism-percentual", 0, "Elitism percentual")
+		opts.seed = fs.Int64("seed", 0, "Seed of the random source (0 = random)")
+		opts.workers = fs.Int("workers", 0, "Goroutines that evaluate the population without a window (0 = GOMAXPROCS)")
+		opts.fitness = fs.String("fitness", "column", "Fitness function ("+strings.Join(Maze.FitnessNames(), ", ")+")")
+		opts.selection = fs.String("selection", "tournament", "Selection strategy ("+strings.Join(Maze.SelectionNames(), ", ")+")")
+		opts.rank_pressure = fs.Float64("rank-pressure", 0, "Expected copies of the best individual on rank selection (1.0 - 2.0)")
+		opts.truncation_ratio = fs.Float64("truncation-ratio", 0, "Fraction of the best individuals that can be parents on truncation selection")
+		opts.boltzmann_temp = fs.Float64("boltzmann-temperature", 0, "Initial temperature of boltzmann selection")
+		opts.boltzmann_cooling = fs.Float64("boltzmann-cooling", 0, "Temperature multiplier applied each generation on boltzmann selection")
+		opts.crossover = fs.String("crossover", "single", "Crossover operator ("+strings.Join(Maze.CrossoverNames(), ", ")+")")
+		opts.crossover_points = fs.Int("crossover-points", 0, "Number of cut points of k-point crossover")
+		opts.uniform_rate = fs.Float64("uniform-rate", 0, "Chance of swapping each gene on uniform crossover")
+		opts.crossover_aligned = fs.Bool("crossover-aligned", false, "Cut just between commands (pairs of genes)")
+		opts.mutation_mix = fs.String("mutation-mix", "flip", "Mutation operators and weights, e.g. flip:2,swap:1 ("+strings.Join(Maze.MutationNames(), ", ")+")")
+		opts.adaptive_stag = fs.Int("adaptive-stagnation", 0, "Generations without improvement to raise the mutation rate (0 = disabled)")
+		opts.adaptive_factor = fs.Float64("adaptive-factor", 0, "Multiplier applied to the mutation rate by the adaptive mutation")
+		opts.adaptive_max_rate = fs.Float64("adaptive-max-rate", 0, "Maximum mutation rate of the adaptive mutation")
+	}
+
+	// [Settings] - Also used by the human player
+	if command != "render" {
+		opts.enemy_collision = fs.String("enemy-collision", "kill", "Players caught by an enemy die or go back to the start (kill, restart)")
+	}
+
+	// Mode specific flags
+	if command == "" || command == "evolve" {
+		opts.headless = fs.Bool("headless", false, "Run the genetic algorithm without opening a window")
+	}
+	if command == "render" {
+		opts.output = fs.String("output", "maze.png", "PNG file to be created")
+	}
+	if command == "edit" {
+		opts.size = fs.String("size", "15x10", "Size of a new map (width x height)")
+	}
+
+	fs.Usage = func() {
+		usage()
+		fmt.Printf("\nFlags of '%s' (override the values of the INI file):\n", fs.Name())
+		fs.PrintDefaults()
+	}
+
+	return fs, opts
+}
+
+// Set the flags defined on command line, overriding the values read from the INI file
+func apply_flags(fs *flag.FlagSet, opts *options) {
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "map":
+			config.Map = *opts.maze_map
+		case "automation":
+			config.Automation = *opts.automation
+		case "generations":
+			config.Generations = *opts.generations
+		case "population-size":
+			config.Population_size = *opts.population_size
+		case "gene-number":
+			config.Gene_number = *opts.gene_number
+		case "k":
+			config.K = *opts.k
+		case "crossover-rate":
+			config.Crossover_rate = *opts.crossover_rate
+		case "mutation-rate":
+			config.Mutation_rate = *opts.mutation_rate
+		case "elitism-percentual":
+			config.Elitism_percentual = *opts.elitism_percentual
+		case "seed":
+			config.Seed = *opts.seed
+		case "workers":
+			config.Workers = *opts.workers
+		case "fitness":
+			fitness, err := Maze.NewFitness(*opts.fitness)
+			if err != nil {
+				fmt.Printf("Invalid flag 'fitness': %s. Exiting.\n", err)
+				os.Exit(2)
+			}
+			config.Fitness = fitness
+		case "selection":
+			selection_name = *opts.selection
+		case "rank-pressure":
+			selection_params.Rank_pressure = *opts.rank_pressure
+		case "truncation-ratio":
+			selection_params.Truncation_ratio = *opts.truncation_ratio
+		case "boltzmann-temperature":
+			selection_params.Boltzmann_temperature = *opts.boltzmann_temp
+		case "boltzmann-cooling":
+			selection_params.Boltzmann_cooling = *opts.boltzmann_cooling
+		case "crossover":
+			crossover_name = *opts.crossover
+		case "crossover-points":
+			crossover_params.Points = *opts.crossover_points
+		case "uniform-rate":
+			crossover_params.Uniform_rate = *opts.uniform_rate
+		case "crossover-aligned":
+			crossover_params.Aligned = *opts.crossover_aligned
+		case "mutation-mix":
+			mix, err := Maze.ParseMutationMix(*opts.mutation_mix)
+			if err != nil {
+				fmt.Printf("Invalid flag 'mutation-mix': %s. Exiting.\n", err)
+				os.Exit(2)
+			}
+			config.Mutation_mix = mix
+		case "adaptive-stagnation":
+			config.Adaptive_mutation.Stagnation = *opts.adaptive_stag
+		case "adaptive-factor":
+			config.Adaptive_mutation.Factor = *opts.adaptive_factor
+		case "adaptive-max-rate":
+			config.Adaptive_mutation.Max_rate = *opts.adaptive_max_rate
+		case "enemy-collision":
+			config.Enemy_collision = *opts.enemy_collision
+		}
+	})
+}
+
+// Execute the subcommand
+func run_subcommand(command string, opts *options) {
+	switch command {
+	case "play":
+		config.Automation = false
+		run_window()
+
+	case "evolve":
+		config.Automation = true
+		if *opts.headless {
+			run_headless()
+		} else {
+			run_window()
+		}
+
+	case "solve":
+		if err := Maze.Solve(config); err != nil {
+			fmt.Printf("\n%s. Exiting\n", err)
+			os.Exit(2)
+		}
+
+	case "edit":
+		if len(opts.files) != 1 {
+			fmt.Printf("Usage: maze edit [--size 20x10] <file>\n")
+			os.Exit(2)
+		}
+		pixelgl.Run(func() {
+			Maze.Edit(config, opts.files[0], *opts.size)
+		})
+
+	case "render":
+		if err := Maze.RenderPNG(config, *opts.output); err != nil {
+			fmt.Printf("Error rendering map: %s. Exiting.\n", err)
+			os.Exit(2)
+		}
+		fmt.Printf("Map %s saved to %s\n", config.Map, *opts.output)
+
+	default:
+		// Mode defined by the INI file
+		if *opts.headless {
+			run_headless()
+			return
+		}
+
+		// Start Window system
+		run_window()
+	}
+}
+
+// Start the Window system
+func run_window() {
+	pixelgl.Run(func() {
+		Maze.Run(config)
+	})
+}
+
+// Run all generations without a window
+func run_headless() {
+	if _, err := Maze.RunHeadless(config); err != nil {
+		fmt.Printf("\n%s. Exiting\n", err)
+		os.Exit(2)
+	}
+}
+
+// Check the map files, exits with status 1 if any of them has problems
+func lint_maps(files []string) {
+	if len(files) == 0 {
+		fmt.Printf("Usage: maze lint-map <file>...\n")
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, file := range files {
+		issues := Maze.LintMap(file)
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", file, issue)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s: ok\n", file)
+		} else {
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Print the available subcommands
+func usage() {
+	fmt.Printf("Usage: maze [command] [flags]\n\nCommands:\n")
+	for _, cmd := range subcommands {
+		fmt.Printf("  %-9s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Printf("\nWithout a command, the mode is defined by the INI file ([Mode] Automation).\n")
+}
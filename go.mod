@@ -2,16 +2,19 @@ module Maze_Game
 
 go 1.19
 
+require (
+	github.com/faiface/pixel v0.10.0
+	golang.org/x/image v0.1.0
+	gopkg.in/ini.v1 v1.67.0
+)
+
 require (
 	github.com/akavel/rsrc v0.10.2 // indirect
 	github.com/faiface/glhf v0.0.0-20181018222622-82a6317ac380 // indirect
 	github.com/faiface/mainthread v0.0.0-20171120011319-8b78f0a41ae3 // indirect
-	github.com/faiface/pixel v0.10.0 // indirect
 	github.com/go-gl/gl v0.0.0-20190320180904-bf2b1f2f34d7 // indirect
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20191125211704-12ad95a8df72 // indirect
 	github.com/go-gl/mathgl v0.0.0-20190416160123-c4601bc793c7 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
 	github.com/pwaller/goupx v0.0.0-20160623083017-1d58e01d5ce2 // indirect
-	golang.org/x/image v0.1.0 // indirect
-	gopkg.in/ini.v1 v1.67.0 // indirect
 )
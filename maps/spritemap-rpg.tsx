@@ -0,0 +1,28 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="spritemap-rpg" tilewidth="130" tileheight="150" tilecount="4" columns="0">
+ <grid orientation="orthogonal" width="1" height="1"/>
+ <tile id="0" x="0" y="119" width="130" height="150">
+  <properties>
+   <property name="tile" type="int" value="1"/>
+  </properties>
+  <image source="../Images/spritemap-rpg.png" width="607" height="569"/>
+ </tile>
+ <tile id="1" x="130" y="119" width="130" height="150">
+  <properties>
+   <property name="tile" type="int" value="2"/>
+  </properties>
+  <image source="../Images/spritemap-rpg.png" width="607" height="569"/>
+ </tile>
+ <tile id="2" x="260" y="119" width="130" height="150">
+  <properties>
+   <property name="tile" type="int" value="3"/>
+  </properties>
+  <image source="../Images/spritemap-rpg.png" width="607" height="569"/>
+ </tile>
+ <tile id="3" x="390" y="119" width="130" height="150">
+  <properties>
+   <property name="tile" type="int" value="4"/>
+  </properties>
+  <image source="../Images/spritemap-rpg.png" width="607" height="569"/>
+ </tile>
+</tileset>
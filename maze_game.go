@@ -7,23 +7,83 @@ import (
 	"runtime"
 	"strconv"
 
-	"github.com/faiface/pixel/pixelgl"
 	"gopkg.in/ini.v1"
 )
 
 var (
 	// Configuration file (ini)
 	maze_ini string = ""
+
+	// Simulation settings filled with INI information and command line flags
+	config Maze.Config
+
+	// Selection strategy, created after reading the INI file and the flags
+	selection_name   string = "tournament"
+	selection_params        = Maze.Default_selection_params
+
+	// Crossover operator, created after reading the INI file and the flags
+	crossover_name   string = "single"
+	crossover_params        = Maze.Default_crossover_params
 )
 
+// Initial INI file, written when the user doesn't have one
+const default_ini = `[Maps]
+map=1			; map name of the maps directory (0 - 3), file path or random:<generator>:<width>x<height>:seed=<seed> (backtracker, prim, kruskal, wilson, forest)
+
+[Mode]
+Automation=true		; true || false
+
+[Settings]
+Generations=100
+Population_size=100
+Gene_number=50
+K=25
+Crossover_rate=0.7
+Mutation_rate=0.05
+Elitism_percentual=10
+Seed=0			; 0 = random
+Fitness=column		; column || distance || steps || bump || coverage
+Selection=tournament	; tournament || roulette || rank || sus || truncation || boltzmann
+Rank_pressure=1.5
+Truncation_ratio=0.5
+Boltzmann_temperature=100
+Boltzmann_cooling=0.95
+Crossover=single	; single || two-point || k-point || uniform || command || same-position
+Crossover_points=3
+Uniform_rate=0.5
+Crossover_aligned=false	; true = cut just between commands
+Mutation_mix=flip	; flip, replace, swap, insert, delete, scramble (e.g. flip:2,swap:1)
+Adaptive_stagnation=0	; generations without improvement to raise the mutation rate (0 = disabled)
+Adaptive_factor=2
+Adaptive_max_rate=0.3
+Enemy_collision=kill	; kill || restart (players caught by an enemy)
+`
+
 // Main function
 func main() {
 
+	// Read the subcommand and its command line flags
+	command, args := parse_subcommand(os.Args[1:])
+	fs, opts := define_flags(command)
+	fs.Parse(args)
+	opts.files = fs.Args()
+
+	// Checking the maps doesn't need the INI file
+	if command == "lint-map" {
+		lint_maps(opts.files)
+		return
+	}
+
 	// Load INI Variables
 	load_INI()
 
-	// Start Window system
-	pixelgl.Run(Maze.Run)
+	// Command line flags take precedence over the INI values
+	apply_flags(fs, opts)
+	define_selection()
+	define_crossover()
+
+	// Execute the subcommand
+	run_subcommand(command, opts)
 }
 
 func load_INI() {
@@ -38,73 +98,32 @@ func load_INI() {
 		os.Exit(2)
 	}
 
-	if myos == "darwin" {
+	if myos == "darwin" || myos == "linux" {
 		maze_ini = home + "/.maze.ini"
-
-		// Check if the ini file already exist
-		if _, err := os.Stat(maze_ini); err != nil || os.IsNotExist(err) {
-			// File not found, create
-			f, err := os.Create(maze_ini)
-			if err != nil {
-				fmt.Printf("Error creating ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
-			defer f.Close()
-
-			// Write initial INI Values
-			_, err2 := f.WriteString("[Maps]\nmap=1\t\t\t; 0 || 1\n\n[Mode]\nAutomation=true\t\t; true || false\n\n[Settings]\nGenerations=100\nPopulation_size=100\nGene_number=50\nK=25\nCrossover_rate=0.7\nMutation_rate=0.05\nElitism_percentual=10\n")
-			if err2 != nil {
-				fmt.Printf("Error writing to ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
-		}
-
 	} else if myos == "windows" {
 		// Windows
 		maze_ini = home + "\\.maze.ini"
+	} else {
+		fmt.Printf("Operational system not supported: %s. Exiting\n\n", myos)
+		os.Exit(2)
+	}
 
-		// Check if the ini file already exist
-		if _, err := os.Stat(maze_ini); err != nil || os.IsNotExist(err) {
-			// File not found, create
-			f, err := os.Create(maze_ini)
-			if err != nil {
-				fmt.Printf("Error creating ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
-			defer f.Close()
-
-			// Write initial INI Values
-			_, err2 := f.WriteString("[Maps]\nmap=1\t\t\t; 0 || 1\n\n[Mode]\nAutomation=true\t\t; true || false\n\n[Settings]\nGenerations=100\nPopulation_size=100\nGene_number=50\nK=25\nCrossover_rate=0.7\nMutation_rate=0.05\nElitism_percentual=10\n")
-			if err2 != nil {
-				fmt.Printf("Error writing to ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
+	// Check if the ini file already exist
+	if _, err := os.Stat(maze_ini); err != nil || os.IsNotExist(err) {
+		// File not found, create
+		f, err := os.Create(maze_ini)
+		if err != nil {
+			fmt.Printf("Error creating ini file: %s. Exiting.", err)
+			os.Exit(2)
 		}
+		defer f.Close()
 
-	} else if myos == "linux" {
-		// Linux
-		maze_ini = home + "/.maze.ini"
-
-		// Check if the ini file already exist
-		if _, err := os.Stat(maze_ini); err != nil || os.IsNotExist(err) {
-			// File not found, create
-			f, err := os.Create(maze_ini)
-			if err != nil {
-				fmt.Printf("Error creating ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
-			defer f.Close()
-
-			// Write initial INI Values
-			_, err2 := f.WriteString("[Maps]\nmap=1\t\t\t; 0 || 1\n\n[Mode]\nAutomation=true\t\t; true || false\n\n[Settings]\nGenerations=100\nPopulation_size=100\nGene_number=50\nK=25\nCrossover_rate=0.7\nMutation_rate=0.05\nElitism_percentual=10\n")
-			if err2 != nil {
-				fmt.Printf("Error writing to ini file: %s. Exiting.", err)
-				os.Exit(2)
-			}
+		// Write initial INI Values
+		_, err2 := f.WriteString(default_ini)
+		if err2 != nil {
+			fmt.Printf("Error writing to ini file: %s. Exiting.", err2)
+			os.Exit(2)
 		}
-	} else {
-		fmt.Printf("Operational system not supported: %s. Exiting\n\n", myos)
-		os.Exit(2)
 	}
 
 	// Load INI information:
@@ -117,15 +136,15 @@ func load_INI() {
 	// ------------ Read ini options into program variables ------------ //
 
 	// [Maps]
-	tmp_value, err := strconv.ParseInt(cfg_ini.Section("Maps").Key("map").String(), 0, 32)
-	Maze.Maze_map = int(tmp_value)
-	if err != nil {
-		fmt.Printf("Fail to read ini attribute 'map': %s", err)
+	config.Map = cfg_ini.Section("Maps").Key("map").String()
+	if config.Map == "" {
+		fmt.Printf("Fail to read ini attribute 'map': empty value")
 		os.Exit(2)
 	}
 
 	// [Mode] - Automation
-	Maze.Automation, err = strconv.ParseBool(cfg_ini.Section("Mode").Key("Automation").String())
+	var tmp_value int64
+	config.Automation, err = strconv.ParseBool(cfg_ini.Section("Mode").Key("Automation").String())
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'automation': %s", err)
 		os.Exit(2)
@@ -133,7 +152,7 @@ func load_INI() {
 
 	// [Settings] - Population_size
 	tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Population_size").String(), 0, 32)
-	Maze.Population_size = int(tmp_value)
+	config.Population_size = int(tmp_value)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'population_size': %s", err)
 		os.Exit(2)
@@ -141,7 +160,7 @@ func load_INI() {
 
 	// [Settings] - Gene_number
 	tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Gene_number").String(), 0, 32)
-	Maze.Gene_number = int(tmp_value)
+	config.Gene_number = int(tmp_value)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'Gene_number': %s", err)
 		os.Exit(2)
@@ -149,21 +168,21 @@ func load_INI() {
 
 	// [Settings] - K
 	tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("K").String(), 0, 8)
-	Maze.K = int(tmp_value)
+	config.K = int(tmp_value)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'K': %s", err)
 		os.Exit(2)
 	}
 
 	// [Settings] - Crossover_rate
-	Maze.Crossover_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Crossover_rate").String(), 0)
+	config.Crossover_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Crossover_rate").String(), 0)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'Crossover_rate': %s", err)
 
 	}
 
 	// [Settings] - Mutation_rate
-	Maze.Mutation_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Mutation_rate").String(), 0)
+	config.Mutation_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Mutation_rate").String(), 0)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'Mutation_rate': %s", err)
 
@@ -171,7 +190,7 @@ func load_INI() {
 
 	// [Settings] - Generations
 	tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Generations").String(), 0, 32)
-	Maze.Generations = int(tmp_value)
+	config.Generations = int(tmp_value)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'Generations': %s", err)
 		os.Exit(2)
@@ -179,10 +198,167 @@ func load_INI() {
 
 	// [Settings] - Elitism_percentual
 	tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Elitism_percentual").String(), 0, 32)
-	Maze.Elitism_percentual = int(tmp_value)
+	config.Elitism_percentual = int(tmp_value)
 	if err != nil {
 		fmt.Printf("Fail to read ini attribute 'Elitism_percentual': %s", err)
 		os.Exit(2)
 	}
 
+	// [Settings] - Fitness (optional, older INI files don't have it)
+	if cfg_ini.Section("Settings").HasKey("Fitness") {
+		config.Fitness, err = Maze.NewFitness(cfg_ini.Section("Settings").Key("Fitness").String())
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Fitness': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Selection (optional, older INI files don't have it)
+	if cfg_ini.Section("Settings").HasKey("Selection") {
+		selection_name = cfg_ini.Section("Settings").Key("Selection").String()
+	}
+
+	// [Settings] - Rank_pressure (optional)
+	if cfg_ini.Section("Settings").HasKey("Rank_pressure") {
+		selection_params.Rank_pressure, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Rank_pressure").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Rank_pressure': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Truncation_ratio (optional)
+	if cfg_ini.Section("Settings").HasKey("Truncation_ratio") {
+		selection_params.Truncation_ratio, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Truncation_ratio").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Truncation_ratio': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Boltzmann_temperature (optional)
+	if cfg_ini.Section("Settings").HasKey("Boltzmann_temperature") {
+		selection_params.Boltzmann_temperature, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Boltzmann_temperature").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Boltzmann_temperature': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Boltzmann_cooling (optional)
+	if cfg_ini.Section("Settings").HasKey("Boltzmann_cooling") {
+		selection_params.Boltzmann_cooling, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Boltzmann_cooling").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Boltzmann_cooling': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Crossover (optional, older INI files don't have it)
+	if cfg_ini.Section("Settings").HasKey("Crossover") {
+		crossover_name = cfg_ini.Section("Settings").Key("Crossover").String()
+	}
+
+	// [Settings] - Crossover_points (optional)
+	if cfg_ini.Section("Settings").HasKey("Crossover_points") {
+		tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Crossover_points").String(), 0, 32)
+		crossover_params.Points = int(tmp_value)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Crossover_points': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Uniform_rate (optional)
+	if cfg_ini.Section("Settings").HasKey("Uniform_rate") {
+		crossover_params.Uniform_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Uniform_rate").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Uniform_rate': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Crossover_aligned (optional)
+	if cfg_ini.Section("Settings").HasKey("Crossover_aligned") {
+		crossover_params.Aligned, err = strconv.ParseBool(cfg_ini.Section("Settings").Key("Crossover_aligned").String())
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Crossover_aligned': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Mutation_mix (optional, older INI files don't have it)
+	if cfg_ini.Section("Settings").HasKey("Mutation_mix") {
+		config.Mutation_mix, err = Maze.ParseMutationMix(cfg_ini.Section("Settings").Key("Mutation_mix").String())
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Mutation_mix': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Adaptive_stagnation (optional)
+	if cfg_ini.Section("Settings").HasKey("Adaptive_stagnation") {
+		tmp_value, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Adaptive_stagnation").String(), 0, 32)
+		config.Adaptive_mutation.Stagnation = int(tmp_value)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Adaptive_stagnation': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Adaptive_factor (optional)
+	if cfg_ini.Section("Settings").HasKey("Adaptive_factor") {
+		config.Adaptive_mutation.Factor, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Adaptive_factor").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Adaptive_factor': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Adaptive_max_rate (optional)
+	if cfg_ini.Section("Settings").HasKey("Adaptive_max_rate") {
+		config.Adaptive_mutation.Max_rate, err = strconv.ParseFloat(cfg_ini.Section("Settings").Key("Adaptive_max_rate").String(), 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Adaptive_max_rate': %s", err)
+			os.Exit(2)
+		}
+	}
+
+	// [Settings] - Enemy_collision (optional)
+	if cfg_ini.Section("Settings").HasKey("Enemy_collision") {
+		config.Enemy_collision = cfg_ini.Section("Settings").Key("Enemy_collision").String()
+	}
+
+	// [Settings] - Seed (optional, older INI files don't have it)
+	if cfg_ini.Section("Settings").HasKey("Seed") {
+		config.Seed, err = strconv.ParseInt(cfg_ini.Section("Settings").Key("Seed").String(), 0, 64)
+		if err != nil {
+			fmt.Printf("Fail to read ini attribute 'Seed': %s", err)
+			os.Exit(2)
+		}
+	}
+
+}
+
+// Create the selection strategy with the parameters of the INI file and flags
+func define_selection() {
+	var err error
+
+	selection_params.K = config.K
+	config.Selection, err = Maze.NewSelection(selection_name, selection_params)
+	if err != nil {
+		fmt.Printf("Invalid selection: %s. Exiting.\n", err)
+		os.Exit(2)
+	}
+}
+
+// Create the crossover operator with the parameters of the INI file and flags
+func define_crossover() {
+	var err error
+
+	config.Crossover, err = Maze.NewCrossover(crossover_name, crossover_params)
+	if err != nil {
+		fmt.Printf("Invalid crossover: %s. Exiting.\n", err)
+		os.Exit(2)
+	}
 }